@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/agent"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/conversation"
+)
+
+// agentFlag switches a command from a plain chat turn to the tool-calling
+// agent loop. It's registered as a persistent flag on rootCmd; the prompt,
+// chat, and conv resume/reply/edit commands honor it, while 'file' always
+// sends a plain turn.
+var agentFlag bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&agentFlag, "agent", false, "run in tool-calling agent mode, letting the model read/write files in the current directory")
+}
+
+// toolCaller asserts that provider supports the agent tool-calling loop,
+// returning a friendly error naming the providers that do if it doesn't.
+func toolCaller(provider backend.Provider) (backend.ToolCaller, error) {
+	caller, ok := provider.(backend.ToolCaller)
+	if !ok {
+		return nil, fmt.Errorf("agent mode is not supported by the active provider yet (only gemini supports tool calling)")
+	}
+	return caller, nil
+}
+
+// buildTools constructs the agent toolset configured in cfg, scoped to the
+// current working directory. run_shell is only enabled when cfg explicitly
+// lists it, since it executes arbitrary commands.
+func buildTools(cfg config.Item) (agent.Toolset, error) {
+	enabled := cfg.Agent.EnabledTools
+	if enabled == nil {
+		enabled = agent.DefaultTools
+	}
+
+	workspaceRoot, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("error resolving workspace root: %w", err)
+	}
+
+	return agent.New(enabled, workspaceRoot, confirmShell), nil
+}
+
+// sendConversationTurn appends userInput to conv and persists the reply,
+// running the tool-calling agent loop instead of a plain turn when
+// agentFlag is set.
+func sendConversationTurn(ctx context.Context, cfg config.Item, provider backend.Provider, conv *conversation.Conversation, userInput string) error {
+	if agentFlag {
+		caller, err := toolCaller(provider)
+		if err != nil {
+			return err
+		}
+		tools, err := buildTools(cfg)
+		if err != nil {
+			return err
+		}
+		return chat.RunAgentConversation(ctx, caller, tools, conv, userInput)
+	}
+	return chat.SendMessageConversation(ctx, provider, conv, userInput, writeOptions())
+}
+
+// runAgentChatConversation is the line-based REPL bound to a persistent
+// conversation, running every turn through the tool-calling agent loop.
+func runAgentChatConversation(ctx context.Context, caller backend.ToolCaller, tools agent.Toolset, conv *conversation.Conversation) error {
+	fmt.Printf("Resuming conversation %s in agent mode (type 'exit' or 'quit' to end).\n", conv.ID)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("You: ")
+		userInput, _ := reader.ReadString('\n')
+		userInput = strings.TrimSpace(userInput)
+
+		if strings.ToLower(userInput) == "exit" || strings.ToLower(userInput) == "quit" {
+			fmt.Println("Exiting chat.")
+			return nil
+		}
+
+		if userInput == "" {
+			continue
+		}
+
+		if err := chat.RunAgentConversation(ctx, caller, tools, conv, userInput); err != nil {
+			fmt.Printf("Error during chat: %v\n", err)
+		}
+	}
+}
+
+// confirmShell asks the user on stdin/stdout whether to run command, since
+// run_shell executes whatever the model asks for.
+func confirmShell(command string) bool {
+	fmt.Printf("Assistant wants to run: %s\nAllow? [y/N] ", command)
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	return strings.ToLower(strings.TrimSpace(answer)) == "y"
+}
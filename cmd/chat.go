@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/agent"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/codeblock"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/conversation"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/tui"
+)
+
+// tuiMode, when false, falls back to the original line-based REPL instead of
+// the bubbletea TUI. Useful for non-interactive terminals (CI, pipes).
+var tuiMode bool
+
+var chatCmd = &cobra.Command{
+	Use:   "chat",
+	Short: "Enter interactive chat mode",
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		ctx := c.Context()
+
+		provider, err := newProvider(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		if agentFlag {
+			if c.Flags().Changed("tui") && tuiMode {
+				return fmt.Errorf("--agent doesn't support the TUI yet; pass --tui=false")
+			}
+			caller, err := toolCaller(provider)
+			if err != nil {
+				return err
+			}
+			tools, err := buildTools(cfg)
+			if err != nil {
+				return err
+			}
+			return runPlainAgentChat(ctx, caller, tools)
+		}
+
+		if tuiMode {
+			return tui.Run(ctx, provider)
+		}
+		session := chat.NewSession(provider)
+		session.Write = writeOptions()
+		return runPlainChat(ctx, session)
+	},
+}
+
+func init() {
+	chatCmd.Flags().BoolVar(&tuiMode, "tui", true, "use the interactive bubbletea TUI instead of the plain line REPL")
+}
+
+// runPlainChat is the original line-based REPL, kept as a --tui=false
+// fallback for terminals that can't render the full-screen TUI.
+func runPlainChat(ctx context.Context, session *chat.Session) error {
+	fmt.Println("Entering interactive chat mode (type 'exit' or 'quit' to end).")
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("You: ")
+		userInput, _ := reader.ReadString('\n')
+		userInput = strings.TrimSpace(userInput)
+
+		if strings.ToLower(userInput) == "exit" || strings.ToLower(userInput) == "quit" {
+			fmt.Println("Exiting chat.")
+			return nil
+		}
+
+		if userInput == "" {
+			continue
+		}
+
+		if err := session.Send(ctx, userInput, ""); err != nil {
+			log.Printf("Error during chat: %v", err)
+		}
+	}
+}
+
+// runPlainAgentChat is the line-based REPL for --agent mode: every turn
+// runs the tool-calling agent loop instead of a single request/response.
+func runPlainAgentChat(ctx context.Context, caller backend.ToolCaller, tools agent.Toolset) error {
+	fmt.Println("Entering interactive agent mode (type 'exit' or 'quit' to end).")
+	session := &chat.Session{}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("You: ")
+		userInput, _ := reader.ReadString('\n')
+		userInput = strings.TrimSpace(userInput)
+
+		if strings.ToLower(userInput) == "exit" || strings.ToLower(userInput) == "quit" {
+			fmt.Println("Exiting chat.")
+			return nil
+		}
+
+		if userInput == "" {
+			continue
+		}
+
+		if err := session.SendAgent(ctx, caller, tools, userInput); err != nil {
+			log.Printf("Error during chat: %v", err)
+		}
+	}
+}
+
+// runPlainChatConversation is the line-based REPL bound to a persistent
+// conversation: every turn is loaded onto provider and appended to conv so
+// the session can be resumed later with 'conv resume'.
+func runPlainChatConversation(ctx context.Context, provider backend.Provider, conv *conversation.Conversation, writeOpts codeblock.WriteOptions) error {
+	fmt.Printf("Resuming conversation %s (type 'exit' or 'quit' to end).\n", conv.ID)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("You: ")
+		userInput, _ := reader.ReadString('\n')
+		userInput = strings.TrimSpace(userInput)
+
+		if strings.ToLower(userInput) == "exit" || strings.ToLower(userInput) == "quit" {
+			fmt.Println("Exiting chat.")
+			return nil
+		}
+
+		if userInput == "" {
+			continue
+		}
+
+		if err := chat.SendMessageConversation(ctx, provider, conv, userInput, writeOpts); err != nil {
+			log.Printf("Error during chat: %v", err)
+		}
+	}
+}
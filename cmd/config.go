@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View or update the CLI configuration",
+}
+
+var configSetAPIKeyCmd = &cobra.Command{
+	Use:   "set-api-key <key>",
+	Short: "Set or update the API key for the active provider",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		cfg.Provider = activeProvider(cfg)
+		return chat.SetAPIKey(cfg, args[0])
+	},
+}
+
+var configSetProviderCmd = &cobra.Command{
+	Use:   "set-provider <name>",
+	Short: "Set the default backend provider (gemini, openai, anthropic, ollama)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		cfg.Provider = args[0]
+		if err := config.Save(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Default provider successfully set to %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configSetAPIKeyCmd)
+	configCmd.AddCommand(configSetProviderCmd)
+}
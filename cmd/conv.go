@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/conversation"
+)
+
+var convCmd = &cobra.Command{
+	Use:   "conv",
+	Short: "Manage persistent, branching conversations",
+}
+
+var convNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Start a new persistent conversation",
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		name := activeProvider(cfg)
+		conv, err := conversation.New(name, cfg.Providers[name].Model)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created conversation %s\n", conv.ID)
+		return nil
+	},
+}
+
+var convListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored conversations",
+	RunE: func(c *cobra.Command, args []string) error {
+		convs, err := conversation.List()
+		if err != nil {
+			return err
+		}
+		if len(convs) == 0 {
+			fmt.Println("No conversations found.")
+			return nil
+		}
+		for _, conv := range convs {
+			fmt.Printf("%s\t%s/%s\t%d messages\t%s\n", conv.ID, conv.Provider, conv.Model, len(conv.Messages), conv.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		return nil
+	},
+}
+
+var convRmCmd = &cobra.Command{
+	Use:   "rm <id>",
+	Short: "Delete a conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		return conversation.Remove(args[0])
+	},
+}
+
+var convViewCmd = &cobra.Command{
+	Use:   "view <id>",
+	Short: "Print the active branch of a conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		conv, err := conversation.Load(args[0])
+		if err != nil {
+			return err
+		}
+		for _, msg := range conv.Branch(conv.HeadID) {
+			who := "You"
+			switch msg.Role {
+			case conversation.RoleModel:
+				who = "Assistant"
+			case conversation.RoleTool:
+				who = "Tool"
+			}
+			fmt.Printf("[%s] %s: %s\n", msg.ID[:8], who, msg.Text)
+		}
+		return nil
+	},
+}
+
+var convResumeCmd = &cobra.Command{
+	Use:   "resume <id>",
+	Short: "Resume an interactive chat loop against a stored conversation",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		conv, err := conversation.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Context()
+		providerCfg := cfg.Providers[conv.Provider]
+		providerCfg.Model = conv.Model
+		provider, err := backend.New(ctx, conv.Provider, providerCfg)
+		if err != nil {
+			return err
+		}
+
+		if agentFlag {
+			caller, err := toolCaller(provider)
+			if err != nil {
+				return err
+			}
+			tools, err := buildTools(cfg)
+			if err != nil {
+				return err
+			}
+			return runAgentChatConversation(ctx, caller, tools, conv)
+		}
+
+		return runPlainChatConversation(ctx, provider, conv, writeOptions())
+	},
+}
+
+var convReplyCmd = &cobra.Command{
+	Use:   "reply <id> <message...>",
+	Short: "Send one message to a stored conversation and append the reply",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		conv, err := conversation.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Context()
+		providerCfg := cfg.Providers[conv.Provider]
+		providerCfg.Model = conv.Model
+		provider, err := backend.New(ctx, conv.Provider, providerCfg)
+		if err != nil {
+			return err
+		}
+
+		return sendConversationTurn(ctx, cfg, provider, conv, strings.Join(args[1:], " "))
+	},
+}
+
+var convUndoCmd = &cobra.Command{
+	Use:   "undo <id>",
+	Short: "Revert the most recent file write made while a conversation was active",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		conv, err := conversation.Load(args[0])
+		if err != nil {
+			return err
+		}
+		path, err := conv.Undo()
+		if err != nil {
+			return err
+		}
+		if err := conv.Save(); err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %s\n", path)
+		return nil
+	},
+}
+
+var convEditFork bool
+
+var convEditCmd = &cobra.Command{
+	Use:   "edit <id> <message-id> <new text...>",
+	Short: "Edit a past message and re-prompt from that point",
+	Long: "Edit rewrites the message identified by <message-id> within conversation <id> " +
+		"and re-sends it. By default the message's descendants are discarded, since they " +
+		"reflected the old text; pass --fork to keep the original branch and create a sibling instead.",
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		conv, err := conversation.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		if _, err := conv.Edit(args[1], convEditFork); err != nil {
+			return err
+		}
+
+		ctx := c.Context()
+		providerCfg := cfg.Providers[conv.Provider]
+		providerCfg.Model = conv.Model
+		provider, err := backend.New(ctx, conv.Provider, providerCfg)
+		if err != nil {
+			return err
+		}
+
+		return sendConversationTurn(ctx, cfg, provider, conv, strings.Join(args[2:], " "))
+	},
+}
+
+func init() {
+	convEditCmd.Flags().BoolVar(&convEditFork, "fork", false, "keep the original branch intact and create a new sibling branch instead of discarding it")
+
+	convCmd.AddCommand(convNewCmd)
+	convCmd.AddCommand(convListCmd)
+	convCmd.AddCommand(convRmCmd)
+	convCmd.AddCommand(convViewCmd)
+	convCmd.AddCommand(convResumeCmd)
+	convCmd.AddCommand(convReplyCmd)
+	convCmd.AddCommand(convEditCmd)
+	convCmd.AddCommand(convUndoCmd)
+
+	rootCmd.AddCommand(convCmd)
+}
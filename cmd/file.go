@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/project"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/prompts"
+)
+
+var (
+	fileInclude  []string
+	fileExclude  []string
+	fileMaxBytes int64
+)
+
+var fileCmd = &cobra.Command{
+	Use:   "file <path> [prompt]",
+	Short: "Send a file, directory, or glob to the active provider with an optional prompt",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		target := args[0]
+		prompt := resolvePrompt(args[1:])
+
+		cfg := loadConfigOrFatal()
+		ctx := c.Context()
+
+		provider, err := newProvider(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		session := chat.NewSession(provider)
+		session.Write = writeOptions()
+
+		if isPlainFile(target) {
+			return session.Send(ctx, prompt, target)
+		}
+
+		files, err := project.Collect(target, project.Options{Include: fileInclude, Exclude: fileExclude})
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			fmt.Println("No matching files found.")
+			return nil
+		}
+
+		var inlined strings.Builder
+		var uploads []string
+		for _, f := range files {
+			if f.Size > fileMaxBytes {
+				uploads = append(uploads, f.Abs)
+				continue
+			}
+			data, err := os.ReadFile(f.Abs)
+			if err != nil {
+				return fmt.Errorf("error reading %q: %w", f.Path, err)
+			}
+			fmt.Fprintf(&inlined, "=== %s ===\n%s\n\n", f.Path, data)
+		}
+		fmt.Printf("Attaching %d file(s): %d inlined, %d uploaded.\n", len(files), len(files)-len(uploads), len(uploads))
+
+		if inlined.Len() > 0 {
+			prompt = prompt + "\n\n" + inlined.String()
+		}
+		return session.SendFiles(ctx, prompt, uploads)
+	},
+}
+
+func init() {
+	fileCmd.Flags().StringSliceVar(&fileInclude, "include", nil, "only attach files whose relative path matches one of these globs")
+	fileCmd.Flags().StringSliceVar(&fileExclude, "exclude", nil, "skip files whose relative path matches one of these globs")
+	fileCmd.Flags().Int64Var(&fileMaxBytes, "max-bytes", 64*1024, "files at or under this size are inlined into the prompt; larger files are uploaded instead")
+}
+
+// isPlainFile reports whether target is an existing, non-glob, non-directory
+// path, meaning the original single-file upload path applies.
+func isPlainFile(target string) bool {
+	if strings.ContainsAny(target, "*?[") {
+		return false
+	}
+	info, err := os.Stat(target)
+	return err == nil && !info.IsDir()
+}
+
+// resolvePrompt builds the prompt text from the words following <path>,
+// resolving a single word to a named preset (e.g. "review") if one exists
+// in the prompts directory (see prompts.Dir), and falling back to a default
+// when no words are given.
+func resolvePrompt(args []string) string {
+	if len(args) == 0 {
+		fmt.Println("No specific prompt provided. Using default prompt: 'Describe the contents of these files.'")
+		return "Describe the contents of these files."
+	}
+	if len(args) == 1 {
+		if preset, err := prompts.Load(args[0]); err == nil {
+			return preset
+		}
+	}
+	return strings.Join(args, " ")
+}
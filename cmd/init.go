@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/gemini"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize the CLI tool (required before first use)",
+	RunE: func(c *cobra.Command, args []string) error {
+		return runInit()
+	},
+}
+
+// readInput reads a single line of input from the user, printing prompt first.
+func readInput(prompt string) (string, error) {
+	fmt.Print(prompt)
+	reader := bufio.NewReader(os.Stdin)
+	input, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("error reading input: %w", err)
+	}
+	return strings.TrimSpace(input), nil
+}
+
+func runInit() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if cfg.InitDone {
+		fmt.Println("You have already completed the initialization process.")
+		return nil
+	}
+	fmt.Println("Initializing configuration...")
+
+	name, err := readInput("What is your name (leave blank for anonymous): ")
+	if err != nil {
+		log.Printf("Warning: could not read name: %v\n", err)
+	}
+
+	provider, err := readInput(fmt.Sprintf("Which provider do you want to use (%s) [leave blank for %s]: ", strings.Join(backend.Names(), ", "), config.DefaultProvider))
+	if err != nil {
+		log.Printf("Warning: could not read provider: %v\n", err)
+	}
+	if len(provider) == 0 {
+		provider = config.DefaultProvider
+	}
+
+	apiKey, err := readInput(fmt.Sprintf("Please enter your %s API key (required): ", provider))
+	if err != nil || len(apiKey) == 0 {
+		return fmt.Errorf("API key is required and could not be read")
+	}
+
+	model, err := readInput(fmt.Sprintf("Enter default %s model (e.g., %s) [leave blank for default]: ", provider, gemini.DefaultModel))
+	if err != nil {
+		log.Printf("Warning: could not read model name: %v\n", err)
+	}
+
+	cfg = config.Item{Name: name, InitDone: true}
+	cfg = cfg.WithProvider(provider, config.ProviderConfig{APIKey: apiKey, Model: model})
+
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Configuration saved successfully to %s\n", config.FileName)
+	return nil
+}
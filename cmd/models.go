@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+)
+
+var modelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "List available models, or set the default model, for the active provider",
+}
+
+var modelsSetCmd = &cobra.Command{
+	Use:   "set <model-name>",
+	Short: "Set the default model for the active provider",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		cfg.Provider = activeProvider(cfg)
+		ctx := c.Context()
+
+		provider, err := newProvider(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		return chat.SetModel(ctx, cfg, strings.ToLower(args[0]), provider)
+	},
+}
+
+var modelsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List models available to the active provider",
+	RunE: func(c *cobra.Command, args []string) error {
+		cfg := loadConfigOrFatal()
+		ctx := c.Context()
+
+		provider, err := newProvider(ctx, cfg)
+		if err != nil {
+			return err
+		}
+
+		names, err := provider.ListModels(ctx)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	modelsCmd.AddCommand(modelsSetCmd)
+	modelsCmd.AddCommand(modelsListCmd)
+}
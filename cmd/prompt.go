@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/chat"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt [text...]",
+	Short: "Send a one-off text prompt to the active provider",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(c *cobra.Command, args []string) error {
+		return runPrompt(c.Context(), args)
+	},
+}
+
+// runPrompt sends a single prompt built from args and prints the response.
+// It backs both the explicit 'prompt' subcommand and the root command's
+// fallback for a bare "cli-gemini-tool <text>" invocation.
+func runPrompt(ctx context.Context, args []string) error {
+	cfg := loadConfigOrFatal()
+
+	provider, err := newProvider(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	text := strings.Join(args, " ")
+	if text == "" {
+		fmt.Println("No prompt provided.")
+		return nil
+	}
+
+	if agentFlag {
+		caller, err := toolCaller(provider)
+		if err != nil {
+			return err
+		}
+		tools, err := buildTools(cfg)
+		if err != nil {
+			return err
+		}
+		return (&chat.Session{}).SendAgent(ctx, caller, tools, text)
+	}
+
+	session := chat.NewSession(provider)
+	session.Write = writeOptions()
+	return session.Send(ctx, text, "")
+}
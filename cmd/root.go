@@ -0,0 +1,89 @@
+// Package cmd wires the CLI's cobra command tree. Each subcommand is a thin
+// layer over the library functions in internal/chat, internal/config, and
+// internal/gemini so the same logic is reusable from the TUI.
+package cmd
+
+import (
+	"context"
+	"log"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+// providerFlag overrides cfg.Provider for a single invocation when set via
+// --provider, without changing the persisted default.
+var providerFlag string
+
+var rootCmd = &cobra.Command{
+	Use:   "cli-gemini-tool",
+	Short: "A command-line and TUI client for Gemini, OpenAI, Anthropic, and Ollama",
+	// Sending a bare prompt ("cli-gemini-tool what is a monad") is the most
+	// common invocation, so treat unrecognized args as the prompt rather
+	// than erroring like cobra does by default.
+	Args:               cobra.ArbitraryArgs,
+	DisableFlagParsing: false,
+	RunE: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return c.Help()
+		}
+		return runPrompt(c.Context(), args)
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&providerFlag, "provider", "", "backend provider to use (gemini, openai, anthropic, ollama); overrides the configured default")
+}
+
+// Execute runs the root command, exiting the process on error the same way
+// the original main() did via log.Fatal.
+func Execute() {
+	ctx := context.Background()
+	rootCmd.SetContext(ctx)
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadConfigOrFatal loads config.json, requiring initialization to have been
+// completed. It is used by the commands that need the configuration; 'init'
+// calls config.Load itself.
+func loadConfigOrFatal() config.Item {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !cfg.InitDone {
+		log.Fatal("CLI not initialized. Please run the 'init' command first.")
+	}
+	return cfg
+}
+
+// activeProvider resolves which provider name cfg and --provider select.
+func activeProvider(cfg config.Item) string {
+	if providerFlag != "" {
+		return providerFlag
+	}
+	if cfg.Provider != "" {
+		return cfg.Provider
+	}
+	return config.DefaultProvider
+}
+
+// newProvider builds the backend.Provider selected by cfg and --provider.
+func newProvider(ctx context.Context, cfg config.Item) (backend.Provider, error) {
+	name := activeProvider(cfg)
+	return backend.New(ctx, name, cfg.Providers[name])
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(chatCmd)
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(fileCmd)
+	rootCmd.AddCommand(modelsCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(versionCmd)
+}
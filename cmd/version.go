@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// Version is the CLI's release version.
+const Version = "v1.0.0"
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the CLI version",
+	RunE: func(c *cobra.Command, args []string) error {
+		fmt.Printf("Gemini CLI Version: %s\n", Version)
+		return nil
+	},
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/codeblock"
+)
+
+// yesFlag and dryRunFlag control how code blocks in a response are written
+// to disk. They're registered as persistent flags on rootCmd so they're
+// available to every command that can produce a response with code blocks.
+var (
+	yesFlag    bool
+	dryRunFlag bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&yesFlag, "yes", false, "write every file the model proposes without prompting for confirmation")
+	rootCmd.PersistentFlags().BoolVar(&dryRunFlag, "dry-run", false, "print what code blocks in the response would write to disk without touching it")
+}
+
+// writeOptions builds the codeblock.WriteOptions for the current
+// invocation, scoped to the current working directory. Conversation-backed
+// sends (chat.SendMessageConversation) additionally log every write to the
+// conversation's changelog so 'conv undo' can revert it; one-off sends have
+// no changelog to log to.
+func writeOptions() codeblock.WriteOptions {
+	root, err := os.Getwd()
+	if err != nil {
+		root = "."
+	}
+	return codeblock.WriteOptions{Root: root, Yes: yesFlag, DryRun: dryRunFlag}
+}
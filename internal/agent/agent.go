@@ -0,0 +1,103 @@
+// Package agent drives a tool-calling loop on top of a backend.ToolCaller:
+// it sends the user's message and a toolset's declarations, dispatches the
+// first FunctionCall in each model response to the matching Go-side tool, feeds the
+// result back as a FunctionResponse, and repeats until the model returns a
+// final text answer or a maximum number of iterations is reached. It is
+// inspired by the tool-calling agents in lmcli.
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+)
+
+// MaxIterations caps how many tool-call round trips a single Run will make,
+// so a model that keeps calling tools can't loop forever.
+const MaxIterations = 8
+
+// Tool is a Go-side function the model can invoke by name.
+type Tool interface {
+	Declaration() backend.ToolDeclaration
+	Call(ctx context.Context, args map[string]any) (string, error)
+}
+
+// Toolset is the set of tools available to an agent run, keyed by name.
+type Toolset map[string]Tool
+
+// Declarations returns the backend.ToolDeclaration for every tool in the
+// set, for advertising to the model.
+func (t Toolset) Declarations() []backend.ToolDeclaration {
+	decls := make([]backend.ToolDeclaration, 0, len(t))
+	for _, tool := range t {
+		decls = append(decls, tool.Declaration())
+	}
+	return decls
+}
+
+// CallObserver is notified before each tool call an agent run dispatches, so
+// the caller can render progress such as `assistant is running read_file(...)`.
+type CallObserver func(name string, args map[string]any)
+
+// Run sends userInput to caller alongside history and tools' declarations,
+// dispatching the first FunctionCall part of each response to the matching
+// tool (further FunctionCall parts in the same response are not dispatched)
+// and feeding the result back until the model answers with plain text or
+// MaxIterations is reached. It returns the full updated history (the user
+// turn, every intermediate tool call/response pair, and the final answer)
+// and the final answer text.
+func Run(ctx context.Context, caller backend.ToolCaller, history []backend.Message, userInput string, tools Toolset, observe CallObserver) ([]backend.Message, string, error) {
+	decls := tools.Declarations()
+	parts := []backend.Part{{Text: userInput}}
+
+	for i := 0; i < MaxIterations; i++ {
+		resp, err := caller.SendMessageWithTools(ctx, history, parts, decls)
+		if err != nil {
+			return history, "", err
+		}
+		history = append(history, backend.Message{Role: "user", Parts: parts})
+		history = append(history, resp.Message)
+
+		call := functionCall(resp.Message)
+		if call == nil {
+			return history, resp.Message.Text(), nil
+		}
+
+		if observe != nil {
+			observe(call.Name, call.Args)
+		}
+
+		result := dispatch(ctx, tools, call)
+		parts = []backend.Part{{FunctionResponse: &backend.FunctionResponse{
+			Name:     call.Name,
+			Response: map[string]any{"result": result},
+		}}}
+	}
+
+	return history, "", fmt.Errorf("agent: reached max iterations (%d) without a final answer", MaxIterations)
+}
+
+// functionCall returns the first FunctionCall part in msg, if any.
+func functionCall(msg backend.Message) *backend.FunctionCall {
+	for _, p := range msg.Parts {
+		if p.FunctionCall != nil {
+			return p.FunctionCall
+		}
+	}
+	return nil
+}
+
+// dispatch calls the named tool and renders its result (or any error) as the
+// string that gets fed back to the model.
+func dispatch(ctx context.Context, tools Toolset, call *backend.FunctionCall) string {
+	tool, ok := tools[call.Name]
+	if !ok {
+		return fmt.Sprintf("error: unknown tool %q", call.Name)
+	}
+	out, err := tool.Call(ctx, call.Args)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return out
+}
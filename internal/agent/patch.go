@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/diff"
+)
+
+// applyUnifiedDiff applies a unified diff (as produced by `diff -u` or `git
+// diff`) to files under root, returning the paths it modified. Parsing and
+// hunk application are shared with internal/diff, which the code-block
+// writer's own diff handling is built on; this just writes diff.Parse's
+// results to disk instead of returning them for a confirmation preview.
+func applyUnifiedDiff(root, diffText string) ([]string, error) {
+	changes, err := diff.Parse(root, diffText)
+	if err != nil {
+		return nil, err
+	}
+
+	touched := make([]string, 0, len(changes))
+	for _, change := range changes {
+		path, err := resolvePath(root, change.Path)
+		if err != nil {
+			return touched, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return touched, fmt.Errorf("error creating directory for %q: %w", change.Path, err)
+		}
+		if err := os.WriteFile(path, change.After, 0644); err != nil {
+			return touched, fmt.Errorf("error writing %q: %w", change.Path, err)
+		}
+		touched = append(touched, change.Path)
+	}
+	return touched, nil
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyUnifiedDiffNewFile(t *testing.T) {
+	root := t.TempDir()
+	d := "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,1 @@\n+hello\n"
+
+	touched, err := applyUnifiedDiff(root, d)
+	if err != nil {
+		t.Fatalf("applyUnifiedDiff: %v", err)
+	}
+	if len(touched) != 1 || touched[0] != "new.txt" {
+		t.Fatalf("touched = %v, want [new.txt]", touched)
+	}
+
+	got, err := os.ReadFile(filepath.Join(root, "new.txt"))
+	if err != nil {
+		t.Fatalf("reading written file: %v", err)
+	}
+	if string(got) != "hello\n" {
+		t.Fatalf("content = %q, want %q", got, "hello\n")
+	}
+}
+
+func TestApplyUnifiedDiffRejectsPathEscapingRoot(t *testing.T) {
+	root := t.TempDir()
+	d := "--- a/../outside.txt\n+++ b/../outside.txt\n@@ -0,0 +1,1 @@\n+evil\n"
+
+	if _, err := applyUnifiedDiff(root, d); err == nil {
+		t.Fatal("applyUnifiedDiff succeeded for a diff target escaping root, want an error")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "outside.txt")); !os.IsNotExist(err) {
+		t.Fatal("escaping write landed on disk outside root")
+	}
+}
+
+func TestResolvePathRejectsEscape(t *testing.T) {
+	root := t.TempDir()
+	if _, err := resolvePath(root, "../escape.txt"); err == nil {
+		t.Fatal("resolvePath succeeded for a path escaping root, want an error")
+	}
+	if _, err := resolvePath(root, "nested/file.txt"); err != nil {
+		t.Fatalf("resolvePath rejected an ordinary nested path: %v", err)
+	}
+}
@@ -0,0 +1,313 @@
+package agent
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+)
+
+// DefaultTools is the toolset enabled when the user's config doesn't list
+// one explicitly. run_shell is deliberately excluded, since it can execute
+// arbitrary commands.
+var DefaultTools = []string{"read_file", "write_file", "list_dir", "search", "apply_patch"}
+
+// New builds the tools named in enabled, all scoped to workspaceRoot.
+// confirmShell is consulted by run_shell before executing a command; it is
+// ignored by every other tool.
+func New(enabled []string, workspaceRoot string, confirmShell func(command string) bool) Toolset {
+	available := map[string]Tool{
+		"read_file":   ReadFileTool{Root: workspaceRoot},
+		"write_file":  WriteFileTool{Root: workspaceRoot},
+		"list_dir":    ListDirTool{Root: workspaceRoot},
+		"search":      SearchTool{Root: workspaceRoot},
+		"run_shell":   RunShellTool{Root: workspaceRoot, Confirm: confirmShell},
+		"apply_patch": ApplyPatchTool{Root: workspaceRoot},
+	}
+
+	toolset := make(Toolset, len(enabled))
+	for _, name := range enabled {
+		if tool, ok := available[name]; ok {
+			toolset[name] = tool
+		}
+	}
+	return toolset
+}
+
+// resolvePath joins root and rel, rejecting any result that would escape
+// root, since a tool's path argument comes from the model and isn't trusted.
+func resolvePath(root, rel string) (string, error) {
+	full := filepath.Join(root, rel)
+	cleanRoot := filepath.Clean(root)
+	if full != cleanRoot && !strings.HasPrefix(full, cleanRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the workspace root", rel)
+	}
+	return full, nil
+}
+
+func stringArg(args map[string]any, key string) (string, error) {
+	v, ok := args[key]
+	if !ok {
+		return "", fmt.Errorf("missing required argument %q", key)
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("argument %q must be a string", key)
+	}
+	return s, nil
+}
+
+// ReadFileTool reads the contents of a file in the workspace.
+type ReadFileTool struct{ Root string }
+
+func (t ReadFileTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "read_file",
+		Description: "Read the full contents of a text file in the workspace.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string", "description": "Path to the file, relative to the workspace root."},
+			},
+			"required": []string{"path"},
+		},
+	}
+}
+
+func (t ReadFileTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	rel, err := stringArg(args, "path")
+	if err != nil {
+		return "", err
+	}
+	path, err := resolvePath(t.Root, rel)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading %q: %w", rel, err)
+	}
+	return string(data), nil
+}
+
+// WriteFileTool creates or overwrites a file in the workspace.
+type WriteFileTool struct{ Root string }
+
+func (t WriteFileTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "write_file",
+		Description: "Write content to a file in the workspace, creating or overwriting it.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":    map[string]any{"type": "string", "description": "Path to the file, relative to the workspace root."},
+				"content": map[string]any{"type": "string", "description": "The full content to write."},
+			},
+			"required": []string{"path", "content"},
+		},
+	}
+}
+
+func (t WriteFileTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	rel, err := stringArg(args, "path")
+	if err != nil {
+		return "", err
+	}
+	content, err := stringArg(args, "content")
+	if err != nil {
+		return "", err
+	}
+	path, err := resolvePath(t.Root, rel)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("error creating directory for %q: %w", rel, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("error writing %q: %w", rel, err)
+	}
+	return fmt.Sprintf("wrote %d bytes to %s", len(content), rel), nil
+}
+
+// ListDirTool lists the entries of a directory in the workspace.
+type ListDirTool struct{ Root string }
+
+func (t ListDirTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "list_dir",
+		Description: "List the names of files and directories at a path in the workspace.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string", "description": `Directory to list, relative to the workspace root. Defaults to "."`},
+			},
+		},
+	}
+}
+
+func (t ListDirTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	rel, _ := args["path"].(string)
+	if rel == "" {
+		rel = "."
+	}
+	path, err := resolvePath(t.Root, rel)
+	if err != nil {
+		return "", err
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", fmt.Errorf("error listing %q: %w", rel, err)
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		if e.IsDir() {
+			fmt.Fprintf(&b, "%s/\n", e.Name())
+		} else {
+			fmt.Fprintf(&b, "%s\n", e.Name())
+		}
+	}
+	return b.String(), nil
+}
+
+// SearchTool searches files under a path in the workspace for lines matching
+// a regular expression.
+type SearchTool struct{ Root string }
+
+func (t SearchTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "search",
+		Description: "Search files under a path in the workspace for lines matching a regular expression.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"regex": map[string]any{"type": "string", "description": "Regular expression to search for."},
+				"path":  map[string]any{"type": "string", "description": `Directory to search under, relative to the workspace root. Defaults to "."`},
+			},
+			"required": []string{"regex"},
+		},
+	}
+}
+
+func (t SearchTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	pattern, err := stringArg(args, "regex")
+	if err != nil {
+		return "", err
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+	rel, _ := args["path"].(string)
+	if rel == "" {
+		rel = "."
+	}
+	root, err := resolvePath(t.Root, rel)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			return nil
+		}
+		defer f.Close()
+		relPath, _ := filepath.Rel(t.Root, path)
+		scanner := bufio.NewScanner(f)
+		for lineNum := 1; scanner.Scan(); lineNum++ {
+			if re.MatchString(scanner.Text()) {
+				fmt.Fprintf(&b, "%s:%d: %s\n", relPath, lineNum, scanner.Text())
+			}
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return "", fmt.Errorf("error searching %q: %w", rel, walkErr)
+	}
+	if b.Len() == 0 {
+		return "no matches found", nil
+	}
+	return b.String(), nil
+}
+
+// RunShellTool runs a shell command in the workspace. It is opt-in: callers
+// must enable it explicitly in config, and Confirm (if set) is asked to
+// approve the exact command before it runs.
+type RunShellTool struct {
+	Root    string
+	Confirm func(command string) bool
+}
+
+func (t RunShellTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "run_shell",
+		Description: "Run a shell command in the workspace and return its combined output. Requires user confirmation.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"command": map[string]any{"type": "string", "description": "The shell command to run."},
+			},
+			"required": []string{"command"},
+		},
+	}
+}
+
+func (t RunShellTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	command, err := stringArg(args, "command")
+	if err != nil {
+		return "", err
+	}
+	if t.Confirm != nil && !t.Confirm(command) {
+		return "", fmt.Errorf("user declined to run command")
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = t.Root
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("command exited with error: %w", err)
+	}
+	return string(out), nil
+}
+
+// ApplyPatchTool applies a unified diff to one or more files in the
+// workspace.
+type ApplyPatchTool struct{ Root string }
+
+func (t ApplyPatchTool) Declaration() backend.ToolDeclaration {
+	return backend.ToolDeclaration{
+		Name:        "apply_patch",
+		Description: "Apply a unified diff to one or more files in the workspace.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"unified_diff": map[string]any{"type": "string", "description": "A unified diff, as produced by `diff -u` or `git diff`."},
+			},
+			"required": []string{"unified_diff"},
+		},
+	}
+}
+
+func (t ApplyPatchTool) Call(ctx context.Context, args map[string]any) (string, error) {
+	diff, err := stringArg(args, "unified_diff")
+	if err != nil {
+		return "", err
+	}
+	files, err := applyUnifiedDiff(t.Root, diff)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("patched %s", strings.Join(files, ", ")), nil
+}
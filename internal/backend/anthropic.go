@@ -0,0 +1,202 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+func init() {
+	Register("anthropic", newAnthropicProvider)
+}
+
+const anthropicDefaultModel = "claude-3-5-sonnet-latest"
+const anthropicBaseURL = "https://api.anthropic.com/v1"
+const anthropicVersion = "2023-06-01"
+const anthropicMaxTokens = 4096
+
+type anthropicProvider struct {
+	apiKey string
+	model  string
+}
+
+func newAnthropicProvider(ctx context.Context, cfg config.ProviderConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("anthropic: API key is missing")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = anthropicDefaultModel
+	}
+	return &anthropicProvider{apiKey: cfg.APIKey, model: model}, nil
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func toAnthropicMessages(history []Message, parts []Part) []anthropicMessage {
+	messages := make([]anthropicMessage, 0, len(history)+1)
+	for _, msg := range history {
+		role := msg.Role
+		if role == "model" {
+			role = "assistant"
+		}
+		messages = append(messages, anthropicMessage{Role: role, Content: msg.Text()})
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	messages = append(messages, anthropicMessage{Role: "user", Content: b.String()})
+	return messages
+}
+
+func (p *anthropicProvider) do(ctx context.Context, body map[string]any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicBaseURL+"/messages", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errBody bytes.Buffer
+		errBody.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("anthropic: unexpected status %s: %s", resp.Status, errBody.String())
+	}
+	return resp, nil
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+}
+
+func (p *anthropicProvider) SendMessage(ctx context.Context, history []Message, parts []Part) (Response, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":      p.model,
+		"max_tokens": anthropicMaxTokens,
+		"messages":   toAnthropicMessages(history, parts),
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var out anthropicResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Response{}, fmt.Errorf("anthropic: error decoding response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, block := range out.Content {
+		text.WriteString(block.Text)
+	}
+	return Response{
+		Message:      Message{Role: "model", Parts: []Part{{Text: text.String()}}},
+		FinishReason: out.StopReason,
+	}, nil
+}
+
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+func (p *anthropicProvider) StreamMessage(ctx context.Context, history []Message, parts []Part) (<-chan StreamChunk, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":      p.model,
+		"max_tokens": anthropicMaxTokens,
+		"messages":   toAnthropicMessages(history, parts),
+		"stream":     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				ch <- StreamChunk{Err: err}
+				return
+			}
+			if event.Type == "content_block_delta" {
+				ch <- StreamChunk{Text: event.Delta.Text}
+			}
+			if event.Type == "message_stop" {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			ch <- StreamChunk{Err: err}
+		}
+	}()
+	return ch, nil
+}
+
+// UploadFile has no analogue in the Anthropic messages API used here, which
+// only accepts inline content.
+func (p *anthropicProvider) UploadFile(ctx context.Context, path string) (File, error) {
+	return File{}, fmt.Errorf("anthropic: file upload is not supported by this provider")
+}
+
+func (p *anthropicProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, anthropicBaseURL+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("anthropic: error decoding model list: %w", err)
+	}
+	names := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		names = append(names, m.ID)
+	}
+	return names, nil
+}
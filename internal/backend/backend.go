@@ -0,0 +1,135 @@
+// Package backend defines the provider-agnostic Provider interface the rest of
+// the CLI talks to, so chat history management, code-block extraction, and
+// the TUI don't need to know whether they're ultimately calling Gemini,
+// OpenAI, Anthropic, or a local Ollama server.
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+// Part is one normalized piece of a message: inline text, a reference to a
+// previously uploaded file, or a function call/response exchanged during an
+// agent tool-calling loop.
+type Part struct {
+	Text             string
+	FileURI          string
+	FileMIMEType     string
+	FunctionCall     *FunctionCall
+	FunctionResponse *FunctionResponse
+}
+
+// FunctionCall is a model's request to invoke a Go-side tool by name.
+type FunctionCall struct {
+	Name string
+	Args map[string]any
+}
+
+// FunctionResponse carries a tool's result back to the model.
+type FunctionResponse struct {
+	Name     string
+	Response map[string]any
+}
+
+// ToolDeclaration describes a callable tool to advertise to a provider that
+// supports function calling. Parameters is a JSON-schema-like description of
+// the tool's arguments (the subset of schema keywords providers' function
+// calling APIs understand: "type", "description", "properties", "required",
+// and "items").
+type ToolDeclaration struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+}
+
+// Message is a single turn in a conversation, normalized across providers.
+type Message struct {
+	Role  string // "user" or "model"
+	Parts []Part
+}
+
+// Text concatenates the text parts of a message.
+func (m Message) Text() string {
+	var b strings.Builder
+	for _, p := range m.Parts {
+		b.WriteString(p.Text)
+	}
+	return b.String()
+}
+
+// Response is a provider's answer to a SendMessage/StreamMessage call.
+type Response struct {
+	Message      Message
+	FinishReason string
+}
+
+// File is the result of uploading a file to a provider for use in a
+// subsequent message's parts.
+type File struct {
+	URI      string
+	MIMEType string
+}
+
+// StreamChunk is one piece of an in-progress streamed response.
+type StreamChunk struct {
+	Text string
+	Err  error
+}
+
+// Provider is implemented by every supported backend.
+type Provider interface {
+	// SendMessage sends parts to the model given the prior history and
+	// returns the complete response.
+	SendMessage(ctx context.Context, history []Message, parts []Part) (Response, error)
+	// StreamMessage behaves like SendMessage but delivers the response
+	// incrementally over the returned channel, which is closed when the
+	// response is complete or an error occurs.
+	StreamMessage(ctx context.Context, history []Message, parts []Part) (<-chan StreamChunk, error)
+	// UploadFile uploads the file at path so it can be referenced from a
+	// message's parts.
+	UploadFile(ctx context.Context, path string) (File, error)
+	// ListModels lists the model names available to the configured account.
+	ListModels(ctx context.Context) ([]string, error)
+}
+
+// ToolCaller is implemented by providers that support a tool-calling agent
+// loop. Of the built-in providers only Gemini implements it, so callers
+// type-assert a Provider to ToolCaller before starting an agent run.
+type ToolCaller interface {
+	// SendMessageWithTools behaves like SendMessage but advertises tools to
+	// the model, which may respond with a FunctionCall part instead of text.
+	SendMessageWithTools(ctx context.Context, history []Message, parts []Part, tools []ToolDeclaration) (Response, error)
+}
+
+// Constructor builds a Provider from its configuration.
+type Constructor func(ctx context.Context, cfg config.ProviderConfig) (Provider, error)
+
+var registry = map[string]Constructor{}
+
+// Register adds a provider constructor under name. Providers call this from
+// an init() function so selecting them by name just works.
+func Register(name string, ctor Constructor) {
+	registry[name] = ctor
+}
+
+// New constructs the named provider using cfg.
+func New(ctx context.Context, name string, cfg config.ProviderConfig) (Provider, error) {
+	ctor, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return ctor(ctx, cfg)
+}
+
+// Names returns the registered provider names.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
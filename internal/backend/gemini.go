@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/gemini"
+)
+
+func init() {
+	Register("gemini", newGeminiProvider)
+}
+
+// geminiProvider is the default, original provider: Google's Gemini API via
+// the genai SDK.
+type geminiProvider struct {
+	client *genai.Client
+	model  string
+}
+
+func newGeminiProvider(ctx context.Context, cfg config.ProviderConfig) (Provider, error) {
+	client, err := gemini.NewClient(ctx, cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	model := cfg.Model
+	if model == "" {
+		model = gemini.DefaultModel
+	}
+	return &geminiProvider{client: client, model: model}, nil
+}
+
+func toGenaiHistory(history []Message) []*genai.Content {
+	content := make([]*genai.Content, 0, len(history))
+	for _, msg := range history {
+		content = append(content, &genai.Content{Role: msg.Role, Parts: toGenaiParts(msg.Parts)})
+	}
+	return content
+}
+
+func toGenaiParts(parts []Part) []genai.Part {
+	genaiParts := make([]genai.Part, 0, len(parts))
+	for _, p := range parts {
+		if p.FileURI != "" {
+			genaiParts = append(genaiParts, genai.FileData{MIMEType: p.FileMIMEType, URI: p.FileURI})
+		}
+		if p.Text != "" {
+			genaiParts = append(genaiParts, genai.Text(p.Text))
+		}
+		if p.FunctionResponse != nil {
+			genaiParts = append(genaiParts, genai.FunctionResponse{Name: p.FunctionResponse.Name, Response: p.FunctionResponse.Response})
+		}
+		if p.FunctionCall != nil {
+			genaiParts = append(genaiParts, genai.FunctionCall{Name: p.FunctionCall.Name, Args: p.FunctionCall.Args})
+		}
+	}
+	return genaiParts
+}
+
+func fromGenaiResponse(resp *genai.GenerateContentResponse) Response {
+	var out Response
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return out
+	}
+	out.FinishReason = resp.Candidates[0].FinishReason.String()
+	out.Message.Role = "model"
+	for _, part := range resp.Candidates[0].Content.Parts {
+		switch v := part.(type) {
+		case genai.Text:
+			out.Message.Parts = append(out.Message.Parts, Part{Text: string(v)})
+		case genai.FunctionCall:
+			out.Message.Parts = append(out.Message.Parts, Part{FunctionCall: &FunctionCall{Name: v.Name, Args: v.Args}})
+		}
+	}
+	return out
+}
+
+// toGenaiSchema converts a ToolDeclaration's loose JSON-schema parameters
+// into the subset genai.Schema understands.
+func toGenaiSchema(spec map[string]any) *genai.Schema {
+	if spec == nil {
+		return nil
+	}
+	schema := &genai.Schema{}
+	switch spec["type"] {
+	case "object":
+		schema.Type = genai.TypeObject
+	case "string":
+		schema.Type = genai.TypeString
+	case "number":
+		schema.Type = genai.TypeNumber
+	case "integer":
+		schema.Type = genai.TypeInteger
+	case "boolean":
+		schema.Type = genai.TypeBoolean
+	case "array":
+		schema.Type = genai.TypeArray
+	}
+	if desc, ok := spec["description"].(string); ok {
+		schema.Description = desc
+	}
+	if props, ok := spec["properties"].(map[string]any); ok {
+		schema.Properties = make(map[string]*genai.Schema, len(props))
+		for name, propSpec := range props {
+			if propMap, ok := propSpec.(map[string]any); ok {
+				schema.Properties[name] = toGenaiSchema(propMap)
+			}
+		}
+	}
+	if req, ok := spec["required"].([]string); ok {
+		schema.Required = req
+	}
+	if items, ok := spec["items"].(map[string]any); ok {
+		schema.Items = toGenaiSchema(items)
+	}
+	return schema
+}
+
+func toGenaiTools(tools []ToolDeclaration) []*genai.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	decls := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for _, t := range tools {
+		decls = append(decls, &genai.FunctionDeclaration{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  toGenaiSchema(t.Parameters),
+		})
+	}
+	return []*genai.Tool{{FunctionDeclarations: decls}}
+}
+
+func (p *geminiProvider) chatSession(history []Message, tools []ToolDeclaration) *genai.ChatSession {
+	model := p.client.GenerativeModel(p.model)
+	model.Tools = toGenaiTools(tools)
+	cs := model.StartChat()
+	cs.History = toGenaiHistory(history)
+	return cs
+}
+
+func (p *geminiProvider) SendMessage(ctx context.Context, history []Message, parts []Part) (Response, error) {
+	resp, err := p.chatSession(history, nil).SendMessage(ctx, toGenaiParts(parts)...)
+	if err != nil {
+		return Response{}, fmt.Errorf("gemini: %w", err)
+	}
+	return fromGenaiResponse(resp), nil
+}
+
+// SendMessageWithTools implements backend.ToolCaller using Gemini's native
+// function-calling support.
+func (p *geminiProvider) SendMessageWithTools(ctx context.Context, history []Message, parts []Part, tools []ToolDeclaration) (Response, error) {
+	resp, err := p.chatSession(history, tools).SendMessage(ctx, toGenaiParts(parts)...)
+	if err != nil {
+		return Response{}, fmt.Errorf("gemini: %w", err)
+	}
+	return fromGenaiResponse(resp), nil
+}
+
+func (p *geminiProvider) StreamMessage(ctx context.Context, history []Message, parts []Part) (<-chan StreamChunk, error) {
+	iter := p.chatSession(history, nil).SendMessageStream(ctx, toGenaiParts(parts)...)
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := iter.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				ch <- StreamChunk{Err: err}
+				return
+			}
+			ch <- StreamChunk{Text: fromGenaiResponse(resp).Message.Text()}
+		}
+	}()
+	return ch, nil
+}
+
+func (p *geminiProvider) UploadFile(ctx context.Context, path string) (File, error) {
+	f, err := p.client.UploadFileFromPath(ctx, path, nil)
+	if err != nil {
+		return File{}, fmt.Errorf("gemini: error uploading file '%s': %w", path, err)
+	}
+	return File{URI: f.URI, MIMEType: f.MIMEType}, nil
+}
+
+func (p *geminiProvider) ListModels(ctx context.Context) ([]string, error) {
+	iter := p.client.ListModels(ctx)
+	var names []string
+	for {
+		info, err := iter.Next()
+		if err != nil {
+			break
+		}
+		names = append(names, info.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,177 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+func init() {
+	Register("ollama", newOllamaProvider)
+}
+
+const ollamaDefaultModel = "llama3"
+const ollamaDefaultHost = "http://localhost:11434"
+
+type ollamaProvider struct {
+	host  string
+	model string
+}
+
+func newOllamaProvider(ctx context.Context, cfg config.ProviderConfig) (Provider, error) {
+	host := cfg.Host
+	if host == "" {
+		host = ollamaDefaultHost
+	}
+	model := cfg.Model
+	if model == "" {
+		model = ollamaDefaultModel
+	}
+	return &ollamaProvider{host: strings.TrimRight(host, "/"), model: model}, nil
+}
+
+type ollamaMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func toOllamaMessages(history []Message, parts []Part) []ollamaMessage {
+	messages := make([]ollamaMessage, 0, len(history)+1)
+	for _, msg := range history {
+		role := msg.Role
+		if role == "model" {
+			role = "assistant"
+		}
+		messages = append(messages, ollamaMessage{Role: role, Content: msg.Text()})
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	messages = append(messages, ollamaMessage{Role: "user", Content: b.String()})
+	return messages
+}
+
+func (p *ollamaProvider) do(ctx context.Context, body map[string]any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.host+"/api/chat", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: %w (is `ollama serve` running at %s?)", err, p.host)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errBody bytes.Buffer
+		errBody.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("ollama: unexpected status %s: %s", resp.Status, errBody.String())
+	}
+	return resp, nil
+}
+
+type ollamaChatLine struct {
+	Message ollamaMessage `json:"message"`
+	Done    bool          `json:"done"`
+}
+
+func (p *ollamaProvider) SendMessage(ctx context.Context, history []Message, parts []Part) (Response, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":    p.model,
+		"messages": toOllamaMessages(history, parts),
+		"stream":   false,
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var line ollamaChatLine
+	if err := json.NewDecoder(resp.Body).Decode(&line); err != nil {
+		return Response{}, fmt.Errorf("ollama: error decoding response: %w", err)
+	}
+	return Response{Message: Message{Role: "model", Parts: []Part{{Text: line.Message.Content}}}}, nil
+}
+
+func (p *ollamaProvider) StreamMessage(ctx context.Context, history []Message, parts []Part) (<-chan StreamChunk, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":    p.model,
+		"messages": toOllamaMessages(history, parts),
+		"stream":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var chatLine ollamaChatLine
+			if err := json.Unmarshal([]byte(line), &chatLine); err != nil {
+				ch <- StreamChunk{Err: err}
+				return
+			}
+			ch <- StreamChunk{Text: chatLine.Message.Content}
+			if chatLine.Done {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			ch <- StreamChunk{Err: err}
+		}
+	}()
+	return ch, nil
+}
+
+// UploadFile is not supported: Ollama's chat API takes image bytes inline
+// per-request rather than through a separate upload step.
+func (p *ollamaProvider) UploadFile(ctx context.Context, path string) (File, error) {
+	return File{}, fmt.Errorf("ollama: file upload is not supported by this provider")
+}
+
+func (p *ollamaProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.host+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var list struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("ollama: error decoding model list: %w", err)
+	}
+	names := make([]string, 0, len(list.Models))
+	for _, m := range list.Models {
+		names = append(names, m.Name)
+	}
+	return names, nil
+}
@@ -0,0 +1,201 @@
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+)
+
+func init() {
+	Register("openai", newOpenAIProvider)
+}
+
+const openAIDefaultModel = "gpt-4o-mini"
+const openAIBaseURL = "https://api.openai.com/v1"
+
+type openAIProvider struct {
+	apiKey string
+	model  string
+}
+
+func newOpenAIProvider(ctx context.Context, cfg config.ProviderConfig) (Provider, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("openai: API key is missing")
+	}
+	model := cfg.Model
+	if model == "" {
+		model = openAIDefaultModel
+	}
+	return &openAIProvider{apiKey: cfg.APIKey, model: model}, nil
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+func toOpenAIMessages(history []Message, parts []Part) []openAIMessage {
+	messages := make([]openAIMessage, 0, len(history)+1)
+	for _, msg := range history {
+		role := msg.Role
+		if role == "model" {
+			role = "assistant"
+		}
+		messages = append(messages, openAIMessage{Role: role, Content: msg.Text()})
+	}
+	var b strings.Builder
+	for _, p := range parts {
+		b.WriteString(p.Text)
+	}
+	messages = append(messages, openAIMessage{Role: "user", Content: b.String()})
+	return messages
+}
+
+func (p *openAIProvider) do(ctx context.Context, body map[string]any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, openAIBaseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		var errBody bytes.Buffer
+		errBody.ReadFrom(resp.Body)
+		return nil, fmt.Errorf("openai: unexpected status %s: %s", resp.Status, errBody.String())
+	}
+	return resp, nil
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+func (p *openAIProvider) SendMessage(ctx context.Context, history []Message, parts []Part) (Response, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":    p.model,
+		"messages": toOpenAIMessages(history, parts),
+	})
+	if err != nil {
+		return Response{}, err
+	}
+	defer resp.Body.Close()
+
+	var chatResp openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return Response{}, fmt.Errorf("openai: error decoding response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return Response{}, fmt.Errorf("openai: response had no choices")
+	}
+	choice := chatResp.Choices[0]
+	return Response{
+		Message:      Message{Role: "model", Parts: []Part{{Text: choice.Message.Content}}},
+		FinishReason: choice.FinishReason,
+	}, nil
+}
+
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+func (p *openAIProvider) StreamMessage(ctx context.Context, history []Message, parts []Part) (<-chan StreamChunk, error) {
+	resp, err := p.do(ctx, map[string]any{
+		"model":    p.model,
+		"messages": toOpenAIMessages(history, parts),
+		"stream":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				return
+			}
+			var chunk openAIStreamChunk
+			if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+				ch <- StreamChunk{Err: err}
+				return
+			}
+			if len(chunk.Choices) > 0 {
+				ch <- StreamChunk{Text: chunk.Choices[0].Delta.Content}
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			ch <- StreamChunk{Err: err}
+		}
+	}()
+	return ch, nil
+}
+
+// UploadFile is not supported: the chat-completions API used here has no
+// server-side upload step the way Gemini's Files API does, and toOpenAIMessages
+// only ever sends Part.Text, so silently inlining the file as a data URI
+// would make SendFiles report success while dropping the attachment.
+func (p *openAIProvider) UploadFile(ctx context.Context, path string) (File, error) {
+	return File{}, fmt.Errorf("openai: file upload is not supported by this provider")
+}
+
+type openAIModelList struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+func (p *openAIProvider) ListModels(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, openAIBaseURL+"/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openai: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var list openAIModelList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("openai: error decoding model list: %w", err)
+	}
+	names := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		names = append(names, m.ID)
+	}
+	return names, nil
+}
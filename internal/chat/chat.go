@@ -0,0 +1,242 @@
+// Package chat implements the message send/response loop shared by the CLI
+// commands and the interactive TUI, on top of whichever backend.Provider is
+// configured.
+package chat
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/agent"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/codeblock"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/config"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/conversation"
+)
+
+// Session is an in-memory, non-persisted history accumulator used by the
+// plain REPL and one-off prompt/file commands, where conversation
+// persistence isn't needed.
+type Session struct {
+	Provider backend.Provider
+	History  []backend.Message
+	// Write controls how code blocks in the model's responses are resolved
+	// and confirmed before being saved to disk. The zero value resolves
+	// proposed filenames against the current directory and prompts before
+	// every write.
+	Write codeblock.WriteOptions
+}
+
+// NewSession wraps provider in a fresh, empty Session.
+func NewSession(provider backend.Provider) *Session {
+	return &Session{Provider: provider}
+}
+
+// Send sends userInput (optionally alongside the file at filePath) and
+// prints the response. Every code block in it is saved to disk per s.Write,
+// under the name its "Filename:" directive gives or, without one, a
+// generated block-N name. On success, both the user input and the model's reply are
+// appended to s.History so the next Send call has full context.
+func (s *Session) Send(ctx context.Context, userInput string, filePath string) error {
+	var filePaths []string
+	if filePath != "" {
+		filePaths = []string{filePath}
+	}
+	return s.SendFiles(ctx, userInput, filePaths)
+}
+
+// SendFiles behaves like Send but uploads every path in filePaths alongside
+// userInput, for the directory/glob form of the 'file' command where more
+// than one attachment may need uploading.
+func (s *Session) SendFiles(ctx context.Context, userInput string, filePaths []string) error {
+	parts := []backend.Part{{Text: userInput}}
+
+	for _, filePath := range filePaths {
+		fmt.Printf("Uploading file: %s...\n", filePath)
+		uploaded, err := s.Provider.UploadFile(ctx, filePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("File uploaded successfully! URI: %s\n", uploaded.URI)
+		parts = append([]backend.Part{{FileURI: uploaded.URI, FileMIMEType: uploaded.MIMEType}}, parts...)
+	}
+
+	fmt.Println("You:", userInput)
+	stream, err := s.Provider.StreamMessage(ctx, s.History, parts)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("Assistant: ")
+	text, err := renderStream(stream, s.Write)
+	if err != nil {
+		return err
+	}
+
+	s.History = append(s.History, backend.Message{Role: "user", Parts: parts})
+	s.History = append(s.History, backend.Message{Role: "model", Parts: []backend.Part{{Text: text}}})
+
+	if text == "" {
+		fmt.Println("Received no response content or response was blocked.")
+	}
+	return nil
+}
+
+// SendAgent behaves like Send but runs userInput through the tool-calling
+// agent loop instead of a single request/response turn. The request goes to
+// caller, not s.Provider (which SendAgent doesn't use and may be nil); callers
+// obtain caller by asserting their provider to backend.ToolCaller, since not
+// every provider supports tool calling.
+func (s *Session) SendAgent(ctx context.Context, caller backend.ToolCaller, tools agent.Toolset, userInput string) error {
+	fmt.Println("You:", userInput)
+	updated, answer, err := agent.Run(ctx, caller, s.History, userInput, tools, observeToolCall)
+	if err != nil {
+		return err
+	}
+	s.History = updated
+
+	fmt.Print("Assistant: ")
+	if answer == "" {
+		fmt.Println("Received no response content or response was blocked.")
+		return nil
+	}
+	fmt.Println(answer)
+	return nil
+}
+
+// observeToolCall prints a line noting an in-flight tool call.
+func observeToolCall(name string, args map[string]any) {
+	fmt.Printf("Assistant is running %s(%v)...\n", name, args)
+}
+
+// SendMessageConversation sends userInput to provider within the context of
+// conv: it loads the active branch's history before sending, and on success
+// appends both the user and model turns to conv and persists it. This lets a
+// conversation be resumed across invocations instead of starting fresh every
+// time. writeOpts.Record is overridden to also log every write to conv's
+// changelog, so 'conv undo' can revert it.
+func SendMessageConversation(ctx context.Context, provider backend.Provider, conv *conversation.Conversation, userInput string, writeOpts codeblock.WriteOptions) error {
+	history := conv.History()
+	writeOpts.Record = recordToChangelog(conv, writeOpts.Record)
+
+	fmt.Println("You:", userInput)
+	stream, err := provider.StreamMessage(ctx, history, []backend.Part{{Text: userInput}})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print("Assistant: ")
+	text, err := renderStream(stream, writeOpts)
+	if err != nil {
+		return err
+	}
+
+	conv.Append("", conversation.RoleUser, userInput)
+
+	if text == "" {
+		fmt.Println("Received no response content or response was blocked.")
+		return conv.Save()
+	}
+	conv.Append("", conversation.RoleModel, text)
+
+	return conv.Save()
+}
+
+// RunAgentConversation behaves like SendMessageConversation but runs
+// userInput through the tool-calling agent loop. The user turn is appended
+// to conv first, then every intermediate tool call and its result as a
+// RoleTool entry (visible via 'conv view' but excluded from conv.History()),
+// then the final model answer.
+func RunAgentConversation(ctx context.Context, caller backend.ToolCaller, tools agent.Toolset, conv *conversation.Conversation, userInput string) error {
+	history := conv.History()
+
+	fmt.Println("You:", userInput)
+	updated, answer, err := agent.Run(ctx, caller, history, userInput, tools, observeToolCall)
+	if err != nil {
+		return err
+	}
+
+	conv.Append("", conversation.RoleUser, userInput)
+	for _, msg := range updated[len(history)+1:] {
+		for _, p := range msg.Parts {
+			switch {
+			case p.FunctionCall != nil:
+				conv.Append("", conversation.RoleTool, fmt.Sprintf("called %s(%v)", p.FunctionCall.Name, p.FunctionCall.Args))
+			case p.FunctionResponse != nil:
+				conv.Append("", conversation.RoleTool, fmt.Sprintf("%s -> %v", p.FunctionResponse.Name, p.FunctionResponse.Response["result"]))
+			}
+		}
+	}
+
+	fmt.Print("Assistant: ")
+	if answer == "" {
+		fmt.Println("Received no response content or response was blocked.")
+		return conv.Save()
+	}
+	fmt.Println(answer)
+	conv.Append("", conversation.RoleModel, answer)
+
+	return conv.Save()
+}
+
+// recordToChangelog wraps inner (which may be nil) so every write a code
+// block makes is also logged to conv's changelog and persisted immediately,
+// letting 'conv undo' revert it even if the process exits before the
+// conversation's own turn-append would otherwise save it.
+func recordToChangelog(conv *conversation.Conversation, inner func(codeblock.WriteRecord)) func(codeblock.WriteRecord) {
+	return func(rec codeblock.WriteRecord) {
+		conv.RecordWrite(rec.Path, rec.Before)
+		if err := conv.Save(); err != nil {
+			fmt.Printf("Error saving conversation changelog: %v\n", err)
+		}
+		if inner != nil {
+			inner(rec)
+		}
+	}
+}
+
+// checkIfInArray reports whether element is present in array.
+func checkIfInArray(array []string, element string) bool {
+	for _, val := range array {
+		if val == element {
+			return true
+		}
+	}
+	return false
+}
+
+// SetModel validates model against the models available to the provider
+// and, if valid, persists it as the default for the current provider in cfg.
+func SetModel(ctx context.Context, cfg config.Item, model string, provider backend.Provider) error {
+	available, err := provider.ListModels(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !checkIfInArray(available, model) {
+		return fmt.Errorf("invalid model name: %s (available: %s)", model, strings.Join(available, ", "))
+	}
+
+	providerCfg := cfg.Current()
+	providerCfg.Model = model
+	cfg = cfg.WithProvider(cfg.Provider, providerCfg)
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+
+	fmt.Printf("Default model successfully set to %s\n", model)
+	return nil
+}
+
+// SetAPIKey persists apiKey for the current provider in cfg.
+func SetAPIKey(cfg config.Item, apiKey string) error {
+	providerCfg := cfg.Current()
+	providerCfg.APIKey = apiKey
+	cfg = cfg.WithProvider(cfg.Provider, providerCfg)
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	fmt.Println("API Key updated successfully.")
+	return nil
+}
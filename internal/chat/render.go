@@ -0,0 +1,82 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/codeblock"
+)
+
+// boldRenderer turns "**bold**" markers into ANSI bold toggles as text
+// streams in, a few bytes at a time, rather than the naive
+// strings.ReplaceAll(text, "**", ...) that only worked on a complete
+// response.
+type boldRenderer struct {
+	bold    bool
+	pending string // held back in case a trailing "*" is about to become "**"
+}
+
+// Write renders chunk, returning the text ready to print immediately.
+// Anything that might still be the first half of a "**" marker is held back
+// until the next call.
+func (b *boldRenderer) Write(chunk string) string {
+	b.pending += chunk
+	var out strings.Builder
+	for {
+		idx := strings.Index(b.pending, "**")
+		if idx == -1 {
+			if strings.HasSuffix(b.pending, "*") {
+				out.WriteString(b.pending[:len(b.pending)-1])
+				b.pending = b.pending[len(b.pending)-1:]
+			} else {
+				out.WriteString(b.pending)
+				b.pending = ""
+			}
+			break
+		}
+		out.WriteString(b.pending[:idx])
+		if b.bold {
+			out.WriteString("\x1b[0m")
+		} else {
+			out.WriteString("\x1b[1m")
+		}
+		b.bold = !b.bold
+		b.pending = b.pending[idx+2:]
+	}
+	return out.String()
+}
+
+// Close flushes any held-back text and closes an unterminated bold span.
+func (b *boldRenderer) Close() string {
+	out := b.pending
+	b.pending = ""
+	if b.bold {
+		out += "\x1b[0m"
+		b.bold = false
+	}
+	return out
+}
+
+// renderStream prints a streamed response as it arrives - rendering bold
+// markers and writing out any code blocks per writeOpts - and returns the
+// full response text once the stream completes.
+func renderStream(stream <-chan backend.StreamChunk, writeOpts codeblock.WriteOptions) (string, error) {
+	var full strings.Builder
+	var bold boldRenderer
+	files := codeblock.NewFileWriter(writeOpts)
+	defer files.Close()
+
+	for chunk := range stream {
+		if chunk.Err != nil {
+			fmt.Println()
+			return full.String(), chunk.Err
+		}
+		full.WriteString(chunk.Text)
+		fmt.Print(bold.Write(chunk.Text))
+		files.Feed(chunk.Text)
+	}
+	fmt.Print(bold.Close())
+	fmt.Println()
+	return full.String(), nil
+}
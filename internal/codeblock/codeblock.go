@@ -0,0 +1,537 @@
+// Package codeblock extracts fenced code blocks from a model's streamed
+// response and, once a block and any "Filename:" directive following it
+// have fully arrived, writes it to disk - resolving the proposed path
+// against a workspace root, previewing a diff against whatever's already
+// there, and prompting for confirmation unless the caller opts out.
+package codeblock
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/diff"
+)
+
+// LanguageExtensions maps common language identifiers to file extensions.
+var LanguageExtensions = map[string]string{
+	"python":     "py",
+	"javascript": "js",
+	"java":       "java",
+	"c":          "c",
+	"cpp":        "cpp",
+	"csharp":     "cs",
+	"go":         "go",
+	"ruby":       "rb",
+	"php":        "php",
+	"swift":      "swift",
+	"kotlin":     "kt",
+	"typescript": "ts",
+	"html":       "html",
+	"css":        "css",
+	"json":       "json",
+	"xml":        "xml",
+	"yaml":       "yaml",
+	"sql":        "sql",
+	"bash":       "sh",
+	"rust":       "rs",
+	// Add more as needed
+}
+
+// fence is the fenced-code-block delimiter.
+const fence = "```"
+
+// Regular expression to find filename directives *after* code blocks.
+// Case-insensitive, handles optional bold markers and backticks around filename.
+var filenameDirectiveRegex = regexp.MustCompile("(?i)(?:\\*\\*)?Filename:(?:\\*\\*)?\\s*`?([^`\n]+)`?")
+
+// EventKind identifies which stage of a streamed code block an Event
+// describes.
+type EventKind int
+
+const (
+	// CodeBlockStart fires once a block's opening fence and language (if
+	// any) have been read.
+	CodeBlockStart EventKind = iota
+	// CodeBlockChunk carries a piece of a block's content as it arrives.
+	CodeBlockChunk
+	// CodeBlockEnd fires once a block's closing fence has been read.
+	CodeBlockEnd
+	// FilenameFound fires when a "Filename:" directive is recognized in the
+	// text following the most recently closed block.
+	FilenameFound
+)
+
+// Event is one step of a streaming code-block extraction, emitted as new
+// response text arrives rather than after the full response is known.
+type Event struct {
+	Kind     EventKind
+	Language string // set on CodeBlockStart
+	Content  string // new content on CodeBlockChunk, the filename on FilenameFound
+}
+
+// Streamer incrementally parses fenced code blocks out of a stream of
+// response chunks, emitting Start/Chunk/End events as fences are recognized
+// instead of requiring the full response up front. Because a "Filename:"
+// directive only ever appears in the text after a block, it is reported
+// separately via FilenameFound once that text has been seen.
+type Streamer struct {
+	pending       string
+	inBlock       bool
+	langDone      bool
+	wantDirective bool // a block just closed and we're scanning for its directive
+	outside       strings.Builder
+}
+
+// Feed appends a new chunk of response text and returns the events it
+// produces.
+func (s *Streamer) Feed(chunk string) []Event {
+	s.pending += chunk
+	var events []Event
+
+	for {
+		if !s.inBlock {
+			idx := strings.Index(s.pending, fence)
+			var outside string
+			if idx == -1 {
+				// Hold back a short tail in case the fence straddles a
+				// chunk boundary.
+				if len(s.pending) <= len(fence)-1 {
+					break
+				}
+				outside = s.pending[:len(s.pending)-(len(fence)-1)]
+				s.pending = s.pending[len(outside):]
+			} else {
+				outside = s.pending[:idx]
+				s.pending = s.pending[idx+len(fence):]
+			}
+
+			if s.wantDirective && outside != "" {
+				s.outside.WriteString(outside)
+			}
+			// idx != -1 means the next fence has arrived, so outside is the
+			// complete text before it and any directive line in it is
+			// necessarily terminated - safe to commit to a match even
+			// without a trailing newline. Otherwise wait for one: the
+			// directive (and a filename split across chunks) may still be
+			// streaming in.
+			if s.wantDirective {
+				if name, ok := matchDirective(s.outside.String(), idx != -1); ok {
+					events = append(events, Event{Kind: FilenameFound, Content: name})
+					s.wantDirective = false
+					s.outside.Reset()
+				}
+			}
+
+			if idx == -1 {
+				break
+			}
+			s.inBlock = true
+			s.langDone = false
+			s.wantDirective = false
+			s.outside.Reset()
+			continue
+		}
+
+		if !s.langDone {
+			nl := strings.IndexByte(s.pending, '\n')
+			if nl == -1 {
+				if !strings.Contains(s.pending, fence) {
+					break
+				}
+				// Closing fence follows immediately: an empty-language block.
+				nl = -1
+			}
+			language := ""
+			if nl != -1 {
+				langPart := strings.TrimSpace(s.pending[:nl])
+				if isValidLanguage(langPart) {
+					language = langPart
+				}
+				s.pending = s.pending[nl+1:]
+			}
+			s.langDone = true
+			events = append(events, Event{Kind: CodeBlockStart, Language: language})
+			continue
+		}
+
+		idx := strings.Index(s.pending, fence)
+		if idx == -1 {
+			if len(s.pending) > len(fence)-1 {
+				flush := s.pending[:len(s.pending)-(len(fence)-1)]
+				s.pending = s.pending[len(flush):]
+				events = append(events, Event{Kind: CodeBlockChunk, Content: flush})
+			}
+			break
+		}
+		if idx > 0 {
+			events = append(events, Event{Kind: CodeBlockChunk, Content: s.pending[:idx]})
+		}
+		s.pending = s.pending[idx+len(fence):]
+		s.inBlock = false
+		s.wantDirective = true
+		events = append(events, Event{Kind: CodeBlockEnd})
+	}
+
+	return events
+}
+
+// Close flushes whatever remains buffered once the stream has ended: an
+// unterminated trailing block, or a directive in the text following the
+// last closed block.
+func (s *Streamer) Close() []Event {
+	var events []Event
+	if s.wantDirective {
+		s.outside.WriteString(s.pending)
+		if name, ok := matchDirective(s.outside.String(), true); ok {
+			events = append(events, Event{Kind: FilenameFound, Content: name})
+		}
+		s.wantDirective = false
+	} else if s.inBlock && s.pending != "" {
+		events = append(events, Event{Kind: CodeBlockChunk, Content: s.pending})
+		events = append(events, Event{Kind: CodeBlockEnd})
+	}
+	s.pending = ""
+	return events
+}
+
+// matchDirective looks for a "Filename:" directive in text and returns the
+// sanitized filename it names, if any. Unless final is set, a match whose
+// captured name runs right up to the end of text is rejected: the directive
+// line may have been cut off mid-name by a stream chunk boundary, and text
+// will grow on the next call. final (at Close, or once the next fence has
+// arrived) says no more text is coming, so that same match is accepted as-is.
+func matchDirective(text string, final bool) (string, bool) {
+	loc := filenameDirectiveRegex.FindStringSubmatchIndex(text)
+	if loc == nil {
+		return "", false
+	}
+	if !final && loc[3] == len(text) {
+		return "", false
+	}
+	name := strings.TrimSpace(text[loc[2]:loc[3]])
+	if name == "" {
+		return "", false
+	}
+	name = strings.ReplaceAll(name, "/", "_")
+	name = strings.ReplaceAll(name, "\\", "_")
+	return filepath.Clean(name), true
+}
+
+// isValidLanguage reports whether langPart looks like a fence's language tag
+// rather than the start of the block's actual content.
+func isValidLanguage(langPart string) bool {
+	if langPart == "" {
+		return false
+	}
+	for _, r := range langPart {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteRecord describes one write the writer made (or would make in dry-run
+// mode) for a single proposed file, for the caller to log as it sees fit -
+// e.g. to a conversation's changelog so 'conv undo' can revert it.
+type WriteRecord struct {
+	// Path is absolute, so reverting it later doesn't depend on the caller's
+	// current directory matching the one this write was made from.
+	Path string
+	// Before is the file's content before the write, or nil if it didn't
+	// exist yet.
+	Before []byte
+}
+
+// WriteOptions controls how a FileWriter resolves, previews, and confirms
+// the files a response's code blocks propose.
+type WriteOptions struct {
+	// Root is the directory every proposed filename is resolved against;
+	// proposals that would escape it are rejected. Defaults to "." if empty.
+	Root string
+	// Yes skips the confirmation prompt and accepts every write, as if the
+	// user answered 'a' (accept all) at the first prompt.
+	Yes bool
+	// DryRun prints what would be written, including a diff preview,
+	// without touching disk.
+	DryRun bool
+	// Record, if set, is called after each write that actually changed (or,
+	// in dry-run mode, would have changed) disk state.
+	Record func(WriteRecord)
+	// Output is where progress and diff previews are printed. Defaults to
+	// os.Stdout; callers driving a full-screen UI (e.g. the TUI, which also
+	// forces Yes so no prompt is ever needed) should set this to io.Discard
+	// since raw writes to stdout would corrupt the alt-screen render.
+	Output io.Writer
+}
+
+func (o WriteOptions) root() string {
+	if o.Root == "" {
+		return "."
+	}
+	return o.Root
+}
+
+func (o WriteOptions) output() io.Writer {
+	if o.Output == nil {
+		return os.Stdout
+	}
+	return o.Output
+}
+
+// FileWriter consumes a Streamer's events and, once each block (and any
+// "Filename:" directive following it) has fully arrived, resolves it to a
+// target file and writes it to disk per its WriteOptions. Content is
+// buffered in memory while a block is open rather than written
+// incrementally, since a write can't be safely previewed or confirmed until
+// the whole block - and its filename - are known.
+type FileWriter struct {
+	opts      WriteOptions
+	streamer  Streamer
+	index     int
+	buf       bytes.Buffer
+	language  string
+	pending   bool // a block has closed and is waiting on a possible directive
+	acceptAll bool // the user answered 'a' to a previous prompt this response
+}
+
+// NewFileWriter returns a FileWriter ready to receive streamed response
+// text, writing files per opts.
+func NewFileWriter(opts WriteOptions) *FileWriter {
+	return &FileWriter{opts: opts}
+}
+
+// Feed streams chunk through the writer, resolving and writing blocks as
+// they close and their filename (if any) is known.
+func (w *FileWriter) Feed(chunk string) {
+	w.handle(w.streamer.Feed(chunk))
+}
+
+// Close flushes any buffered trailing block. It should be called once the
+// response stream ends.
+func (w *FileWriter) Close() {
+	w.handle(w.streamer.Close())
+	w.finalizePending("")
+}
+
+func (w *FileWriter) handle(events []Event) {
+	for _, ev := range events {
+		switch ev.Kind {
+		case CodeBlockStart:
+			w.finalizePending("")
+			w.index++
+			w.language = ev.Language
+			w.buf.Reset()
+
+		case CodeBlockChunk:
+			w.buf.WriteString(ev.Content)
+
+		case CodeBlockEnd:
+			w.pending = true
+
+		case FilenameFound:
+			w.finalizePending(ev.Content)
+		}
+	}
+}
+
+// finalizePending resolves the most recently closed block to a filename -
+// name if a directive was found for it, otherwise one generated from its
+// language - and writes it, unless there's no pending block at all.
+func (w *FileWriter) finalizePending(name string) {
+	if !w.pending {
+		return
+	}
+	w.pending = false
+
+	if name == "" {
+		ext := LanguageExtensions[strings.ToLower(w.language)]
+		if ext == "" {
+			ext = "txt"
+		}
+		name = fmt.Sprintf("block-%d.%s", w.index, ext)
+	}
+	fmt.Fprintf(w.opts.output(), "Found block %d: language=%q\n", w.index, w.language)
+
+	if strings.ToLower(w.language) == "diff" {
+		w.writeDiffBlock(w.buf.Bytes())
+		return
+	}
+
+	w.confirm(name, w.buf.Bytes())
+}
+
+// writeDiffBlock applies a fenced ```diff block as a patch instead of a
+// full-file overwrite, resolving and confirming each file it touches the
+// same way a plain block is.
+func (w *FileWriter) writeDiffBlock(content []byte) {
+	changes, err := diff.Parse(w.opts.root(), string(content))
+	if err != nil {
+		fmt.Fprintf(w.opts.output(), "  Error parsing diff block: %v\n", err)
+		return
+	}
+	for _, change := range changes {
+		w.confirm(change.Path, change.After)
+	}
+}
+
+// confirm previews relPath's change (new file, or a diff against whatever's
+// on disk), prompts unless the writer is set to accept everything, and
+// writes after to disk unless DryRun is set.
+func (w *FileWriter) confirm(relPath string, after []byte) {
+	path, err := resolvePath(w.opts.root(), relPath)
+	if err != nil {
+		fmt.Fprintf(w.opts.output(), "  Skipping %q: %v\n", relPath, err)
+		return
+	}
+	before := readExisting(path)
+
+	if before != nil {
+		if bytes.Equal(before, after) {
+			fmt.Fprintf(w.opts.output(), "  %s is unchanged, skipping.\n", relPath)
+			return
+		}
+		fmt.Fprintf(w.opts.output(), "  %s already exists:\n%s", relPath, diff.Unified(relPath, before, after))
+	} else {
+		fmt.Fprintf(w.opts.output(), "  %s is a new file.\n", relPath)
+	}
+
+	if !w.opts.Yes && !w.acceptAll {
+		switch promptAction(relPath) {
+		case actionSkip:
+			fmt.Fprintf(w.opts.output(), "  Skipped %s.\n", relPath)
+			return
+		case actionAcceptAll:
+			w.acceptAll = true
+		case actionEdit:
+			edited, err := editInEditor(after)
+			if err != nil {
+				fmt.Fprintf(w.opts.output(), "  Error editing %s: %v, skipping.\n", relPath, err)
+				return
+			}
+			after = edited
+		}
+	}
+
+	if w.opts.DryRun {
+		fmt.Fprintf(w.opts.output(), "  [dry run] would write %d bytes to %s\n", len(after), relPath)
+		w.record(path, before)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fmt.Fprintf(w.opts.output(), "  Error creating directory for %q: %v\n", relPath, err)
+		return
+	}
+	if err := os.WriteFile(path, after, 0644); err != nil {
+		fmt.Fprintf(w.opts.output(), "  Error writing %q: %v\n", relPath, err)
+		return
+	}
+	fmt.Fprintf(w.opts.output(), "  Wrote %s\n", relPath)
+	w.record(path, before)
+}
+
+func (w *FileWriter) record(path string, before []byte) {
+	if w.opts.Record != nil {
+		w.opts.Record(WriteRecord{Path: path, Before: before})
+	}
+}
+
+// action is the user's answer to a write confirmation prompt.
+type action int
+
+const (
+	actionAccept action = iota
+	actionSkip
+	actionAcceptAll
+	actionEdit
+)
+
+// promptAction asks the user on stdin/stdout whether to write relPath,
+// reprompting until it gets a recognized answer.
+func promptAction(relPath string) action {
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("  Write %s? [Y/n/a/e] ", relPath)
+		answer, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y":
+			return actionAccept
+		case "n":
+			return actionSkip
+		case "a":
+			return actionAcceptAll
+		case "e":
+			return actionEdit
+		}
+		fmt.Println("  Please answer y, n, a, or e.")
+	}
+}
+
+// editInEditor opens content in $EDITOR (falling back to vi) and returns
+// whatever the user saved, mirroring the $EDITOR integration the TUI offers
+// for composing prompts.
+func editInEditor(content []byte) ([]byte, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "cli-gemini-tool-edit-*")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("error writing temp file: %w", err)
+	}
+	tmp.Close()
+
+	cmd := exec.Command(editor, tmp.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("error running %s: %w", editor, err)
+	}
+
+	return os.ReadFile(tmp.Name())
+}
+
+// readExisting reads path's current content, returning nil rather than an
+// error if it doesn't exist: that just means the proposed write is creating
+// a new file.
+func readExisting(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// resolvePath joins root and rel, rejecting any result that would escape
+// root, since a proposed filename comes from the model and isn't trusted.
+// It works in absolute terms so the containment check holds even when root
+// is "." - filepath.Join cleans away a literal "./" prefix, so comparing
+// against the un-absolutized root would otherwise reject every ordinary
+// relative path.
+func resolvePath(root, rel string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("error resolving workspace root: %w", err)
+	}
+	full := filepath.Join(absRoot, rel)
+	if full != absRoot && !strings.HasPrefix(full, absRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the workspace root", rel)
+	}
+	return full, nil
+}
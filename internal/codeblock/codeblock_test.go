@@ -0,0 +1,76 @@
+package codeblock
+
+import "testing"
+
+// feedAll runs chunks through a fresh Streamer one at a time, as they'd
+// arrive over a real stream, and returns every event produced including
+// whatever Close flushes at the end.
+func feedAll(t *testing.T, chunks ...string) []Event {
+	t.Helper()
+	var s Streamer
+	var events []Event
+	for _, c := range chunks {
+		events = append(events, s.Feed(c)...)
+	}
+	events = append(events, s.Close()...)
+	return events
+}
+
+func TestStreamerFilenameDirectiveSplitAcrossChunks(t *testing.T) {
+	// Regression test for 4e18bd4: a "Filename:" directive whose name was
+	// split across two stream chunks used to be truncated at the chunk
+	// boundary instead of waiting for the rest to arrive.
+	events := feedAll(t, "```go\nfmt.Println(1)\n```\nFilename: main", ".go\n")
+
+	var found string
+	for _, ev := range events {
+		if ev.Kind == FilenameFound {
+			found = ev.Content
+		}
+	}
+	if found != "main.go" {
+		t.Fatalf("filename = %q, want %q", found, "main.go")
+	}
+}
+
+func TestStreamerFenceSplitAcrossChunks(t *testing.T) {
+	events := feedAll(t, "prefix ``", "`go\ncode\n```", "\nFilename: out.go\n")
+
+	var kinds []EventKind
+	var content string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Kind)
+		if ev.Kind == CodeBlockChunk {
+			content += ev.Content
+		}
+	}
+	if content != "code\n" {
+		t.Fatalf("block content = %q, want %q", content, "code\n")
+	}
+
+	wantLast := FilenameFound
+	if len(kinds) == 0 || kinds[len(kinds)-1] != wantLast {
+		t.Fatalf("events = %v, want a trailing FilenameFound", kinds)
+	}
+}
+
+func TestStreamerUnterminatedBlockFlushedOnClose(t *testing.T) {
+	events := feedAll(t, "```python\nprint(1)")
+
+	var gotEnd bool
+	var content string
+	for _, ev := range events {
+		if ev.Kind == CodeBlockChunk {
+			content += ev.Content
+		}
+		if ev.Kind == CodeBlockEnd {
+			gotEnd = true
+		}
+	}
+	if !gotEnd {
+		t.Fatalf("events = %v, want a CodeBlockEnd flushed by Close", events)
+	}
+	if content != "print(1)" {
+		t.Fatalf("block content = %q, want %q", content, "print(1)")
+	}
+}
@@ -0,0 +1,92 @@
+// Package config handles reading and writing the CLI's on-disk configuration
+// (per-provider API keys and default models, and first-run state).
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FileName is the name of the configuration file in the current working
+// directory.
+const FileName = "config.json"
+
+// DefaultProvider is used when the config doesn't specify one, which keeps
+// existing single-provider setups working.
+const DefaultProvider = "gemini"
+
+// ProviderConfig holds the settings for a single backend provider. Not every
+// field applies to every provider: Ollama has no API key but does have a
+// Host, while the hosted providers have no Host but do have an APIKey.
+type ProviderConfig struct {
+	APIKey string `json:"api_key,omitempty"`
+	Model  string `json:"model,omitempty"`
+	Host   string `json:"host,omitempty"`
+}
+
+// AgentConfig holds settings for the tool-calling agent mode.
+type AgentConfig struct {
+	// EnabledTools lists which tools the agent loop may call. A nil slice
+	// means the caller's own default toolset applies.
+	EnabledTools []string `json:"enabled_tools,omitempty"`
+}
+
+// Item holds the user's persisted configuration.
+type Item struct {
+	Name      string                    `json:"name"`
+	InitDone  bool                      `json:"init_done"`
+	Provider  string                    `json:"provider"`
+	Providers map[string]ProviderConfig `json:"providers"`
+	Agent     AgentConfig               `json:"agent,omitempty"`
+}
+
+// Current returns the ProviderConfig for the selected provider, or a zero
+// value if none has been configured yet.
+func (i Item) Current() ProviderConfig {
+	return i.Providers[i.Provider]
+}
+
+// WithProvider returns a copy of i with cfg stored under name and name set
+// as the selected provider.
+func (i Item) WithProvider(name string, cfg ProviderConfig) Item {
+	providers := make(map[string]ProviderConfig, len(i.Providers)+1)
+	for k, v := range i.Providers {
+		providers[k] = v
+	}
+	providers[name] = cfg
+	i.Provider = name
+	i.Providers = providers
+	return i
+}
+
+// Load reads config.json from the current directory. If the file does not
+// exist, it returns an empty Item (InitDone will be false) rather than an
+// error, so callers can decide whether to prompt the user to run 'init'.
+func Load() (Item, error) {
+	jsonData, err := os.ReadFile(FileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Item{}, nil
+		}
+		return Item{}, fmt.Errorf("error reading config file %s: %w", FileName, err)
+	}
+
+	var cfg Item
+	if err := json.Unmarshal(jsonData, &cfg); err != nil {
+		return Item{}, fmt.Errorf("error unmarshaling JSON from %s: %w", FileName, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to config.json, pretty-printed.
+func Save(cfg Item) error {
+	jsonData, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+	if err := os.WriteFile(FileName, jsonData, 0644); err != nil {
+		return fmt.Errorf("error writing JSON file '%s': %w", FileName, err)
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+// Package conversation implements a persistent, branching chat history.
+// Each conversation is stored as an ordered list of messages with parent
+// pointers, so editing a past message can fork a new branch instead of
+// losing the original one, similar to lmcli's conversation model.
+package conversation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+)
+
+// RoleUser and RoleModel identify who authored a Message. RoleTool records a
+// tool call or its result from an agent run, for display in 'conv view'; it
+// is never sent back to a provider as part of History().
+const (
+	RoleUser  = "user"
+	RoleModel = "model"
+	RoleTool  = "tool"
+)
+
+// Message is a single turn in a conversation. ParentID is empty for the
+// first message in a conversation; every other message has exactly one
+// parent, and a parent may have more than one child if the conversation
+// has been forked.
+type Message struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Role      string    `json:"role"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WriteRecord is one disk write a code-block writer made while this
+// conversation was active, kept so 'conv undo' can revert it. Path is
+// absolute, so undoing it doesn't depend on 'conv undo' being run from the
+// same directory the write was made from. Before is nil if the file didn't
+// exist yet, distinguishing "restore to this content" from "the write
+// created the file, so undo should remove it" - note this is deliberately
+// not `json:"before,omitempty"`, since that would conflate a pre-existing
+// empty file with one that didn't exist at all.
+type WriteRecord struct {
+	Path      string    `json:"path"`
+	Before    []byte    `json:"before"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Conversation is a full branching message tree plus metadata about which
+// leaf is currently active (where 'reply' appends next).
+type Conversation struct {
+	ID        string        `json:"id"`
+	Provider  string        `json:"provider"`
+	Model     string        `json:"model"`
+	CreatedAt time.Time     `json:"created_at"`
+	HeadID    string        `json:"head_id"`
+	Messages  []Message     `json:"messages"`
+	Writes    []WriteRecord `json:"writes,omitempty"`
+}
+
+// Dir returns the directory conversations are stored in, creating it if
+// necessary: ~/.config/cli-gemini-tool/conversations/.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error resolving user config dir: %w", err)
+	}
+	dir := filepath.Join(configDir, "cli-gemini-tool", "conversations")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("error creating conversations dir: %w", err)
+	}
+	return dir, nil
+}
+
+func path(dir, id string) string {
+	return filepath.Join(dir, id+".json")
+}
+
+// New creates and persists a fresh, empty conversation bound to provider
+// and model.
+func New(provider, model string) (*Conversation, error) {
+	conv := &Conversation{
+		ID:        uuid.NewString(),
+		Provider:  provider,
+		Model:     model,
+		CreatedAt: time.Now(),
+	}
+	if err := conv.Save(); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// Load reads a conversation by ID.
+func Load(id string) (*Conversation, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path(dir, id))
+	if err != nil {
+		return nil, fmt.Errorf("error reading conversation %s: %w", id, err)
+	}
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("error unmarshaling conversation %s: %w", id, err)
+	}
+	return &conv, nil
+}
+
+// Save persists the conversation to its JSON file.
+func (c *Conversation) Save() error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling conversation: %w", err)
+	}
+	if err := os.WriteFile(path(dir, c.ID), data, 0644); err != nil {
+		return fmt.Errorf("error writing conversation %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// Remove deletes a conversation's file by ID.
+func Remove(id string) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path(dir, id)); err != nil {
+		return fmt.Errorf("error removing conversation %s: %w", id, err)
+	}
+	return nil
+}
+
+// List returns every stored conversation, most recently created first.
+func List() ([]Conversation, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error listing conversations dir: %w", err)
+	}
+
+	var convs []Conversation
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		id := entry.Name()[:len(entry.Name())-len(".json")]
+		conv, err := Load(id)
+		if err != nil {
+			continue
+		}
+		convs = append(convs, *conv)
+	}
+
+	sort.Slice(convs, func(i, j int) bool { return convs[i].CreatedAt.After(convs[j].CreatedAt) })
+	return convs, nil
+}
+
+// byID returns the message with the given ID, if any. id may also be an
+// unambiguous prefix of a message ID, since that's all 'conv view' prints
+// (msg.ID[:8]) and users shouldn't have to go dig up the full UUID to pass
+// to 'conv edit'. An exact match always wins over a prefix match; a prefix
+// matching more than one message is treated as not found, same as no match
+// at all, so callers don't silently act on the wrong message.
+func (c *Conversation) byID(id string) (*Message, bool) {
+	for i := range c.Messages {
+		if c.Messages[i].ID == id {
+			return &c.Messages[i], true
+		}
+	}
+	var match *Message
+	for i := range c.Messages {
+		if strings.HasPrefix(c.Messages[i].ID, id) {
+			if match != nil {
+				return nil, false
+			}
+			match = &c.Messages[i]
+		}
+	}
+	return match, match != nil
+}
+
+// Branch returns the message chain from the root to id, in order.
+func (c *Conversation) Branch(id string) []Message {
+	var chain []Message
+	for id != "" {
+		msg, ok := c.byID(id)
+		if !ok {
+			break
+		}
+		chain = append([]Message{*msg}, chain...)
+		id = msg.ParentID
+	}
+	return chain
+}
+
+// History returns the active branch (root to HeadID) as backend.Message,
+// suitable for passing to a Provider's SendMessage before sending the next
+// message. It is provider-agnostic, so the same stored conversation can be
+// resumed against whichever provider is currently configured.
+func (c *Conversation) History() []backend.Message {
+	chain := c.Branch(c.HeadID)
+	history := make([]backend.Message, 0, len(chain))
+	for _, msg := range chain {
+		if msg.Role == RoleTool {
+			// Tool call/result log entries are a record for 'conv view', not
+			// part of the chat turns a provider expects back.
+			continue
+		}
+		role := "user"
+		if msg.Role == RoleModel {
+			role = "model"
+		}
+		history = append(history, backend.Message{
+			Role:  role,
+			Parts: []backend.Part{{Text: msg.Text}},
+		})
+	}
+	return history
+}
+
+// Append adds a new message as a child of parentID (or of the current head
+// if parentID is empty) and moves the head to it.
+func (c *Conversation) Append(parentID, role, text string) Message {
+	if parentID == "" {
+		parentID = c.HeadID
+	}
+	msg := Message{
+		ID:        uuid.NewString(),
+		ParentID:  parentID,
+		Role:      role,
+		Text:      text,
+		CreatedAt: time.Now(),
+	}
+	c.Messages = append(c.Messages, msg)
+	c.HeadID = msg.ID
+	return msg
+}
+
+// Edit rewinds the conversation to messageID's parent so that the next
+// Append/SendMessageConversation call re-prompts from that point with new
+// text. When fork is false (the default), messageID and its descendants are
+// discarded, since they no longer reflect what follows the edit. When fork
+// is true, messageID and its descendants are left untouched, so the edit
+// becomes a sibling branch instead of replacing history. Either way it
+// returns the parent ID the caller should append the edited message under.
+func (c *Conversation) Edit(messageID string, fork bool) (parentID string, err error) {
+	original, ok := c.byID(messageID)
+	if !ok {
+		return "", fmt.Errorf("message not found: %s", messageID)
+	}
+
+	// original points into c.Messages, which the compaction below rewrites
+	// in place, so copy what's needed out of it first.
+	id := original.ID
+	parentID = original.ParentID
+
+	if !fork {
+		c.discardDescendants(id)
+		kept := c.Messages[:0]
+		for _, msg := range c.Messages {
+			if msg.ID != id {
+				kept = append(kept, msg)
+			}
+		}
+		c.Messages = kept
+	}
+
+	c.HeadID = parentID
+	return parentID, nil
+}
+
+// RecordWrite appends a WriteRecord for a file a code-block writer just
+// wrote to disk while this conversation was active, so a later call to Undo
+// can restore it.
+func (c *Conversation) RecordWrite(path string, before []byte) {
+	c.Writes = append(c.Writes, WriteRecord{Path: path, Before: before, CreatedAt: time.Now()})
+}
+
+// Undo reverts the most recent write in the changelog: restoring the file
+// to its content from before the write, or removing it if the write created
+// it. It pops the reverted record off the changelog and returns the path it
+// touched; it does not persist the conversation itself, since callers
+// typically call Save() right after.
+func (c *Conversation) Undo() (string, error) {
+	if len(c.Writes) == 0 {
+		return "", fmt.Errorf("no writes to undo for conversation %s", c.ID)
+	}
+	rec := c.Writes[len(c.Writes)-1]
+
+	if rec.Before == nil {
+		if err := os.Remove(rec.Path); err != nil && !os.IsNotExist(err) {
+			return "", fmt.Errorf("error removing %q: %w", rec.Path, err)
+		}
+	} else if err := os.WriteFile(rec.Path, rec.Before, 0644); err != nil {
+		return "", fmt.Errorf("error restoring %q: %w", rec.Path, err)
+	}
+
+	c.Writes = c.Writes[:len(c.Writes)-1]
+	return rec.Path, nil
+}
+
+// discardDescendants removes every message whose ancestry passes through id.
+func (c *Conversation) discardDescendants(id string) {
+	toRemove := map[string]bool{}
+	var mark func(parent string)
+	mark = func(parent string) {
+		for _, msg := range c.Messages {
+			if msg.ParentID == parent && !toRemove[msg.ID] {
+				toRemove[msg.ID] = true
+				mark(msg.ID)
+			}
+		}
+	}
+	mark(id)
+
+	kept := c.Messages[:0]
+	for _, msg := range c.Messages {
+		if !toRemove[msg.ID] {
+			kept = append(kept, msg)
+		}
+	}
+	c.Messages = kept
+}
@@ -0,0 +1,269 @@
+// Package diff parses and applies unified diffs (as produced by `diff -u`
+// or `git diff`) and renders a line-level unified diff between two versions
+// of a file's content. It backs the code-block writer's confirmation
+// preview and its handling of fenced ```diff blocks: a full diff/patch
+// library isn't worth the dependency for either.
+package diff
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// FileChange is one target file a unified diff touches: its content after
+// the diff's hunks are applied to whatever Before held.
+type FileChange struct {
+	// Path is relative to the root Parse was called with.
+	Path   string
+	Before []byte // nil if the file doesn't exist yet
+	After  []byte
+}
+
+// Parse computes the FileChanges a unified diff describes, resolving each
+// target path against root and rejecting any that would escape it, without
+// writing anything to disk.
+func Parse(root, diffText string) ([]FileChange, error) {
+	lines := strings.Split(diffText, "\n")
+	var changes []FileChange
+
+	i := 0
+	for i < len(lines) {
+		if !strings.HasPrefix(lines[i], "--- ") {
+			i++
+			continue
+		}
+		if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+			return changes, fmt.Errorf("malformed diff: expected a +++ line after %q", lines[i])
+		}
+		target := stripDiffHeaderPath(lines[i+1][4:])
+		i += 2
+
+		path, err := resolvePath(root, target)
+		if err != nil {
+			return changes, err
+		}
+		original := readFile(path)
+		origLines := strings.Split(string(original), "\n")
+		var out []string
+		cursor := 0
+
+		for i < len(lines) && strings.HasPrefix(lines[i], "@@") {
+			m := hunkHeaderRegex.FindStringSubmatch(lines[i])
+			if m == nil {
+				return changes, fmt.Errorf("malformed hunk header: %q", lines[i])
+			}
+			origStart, _ := strconv.Atoi(m[1])
+			i++
+
+			// A new-file hunk header ("@@ -0,0 +1,N @@") means origStart==0,
+			// not "one before the start" - there's no preceding context to
+			// copy and cursor stays at 0.
+			if origStart == 0 {
+				origStart = 1
+			}
+			if origStart-1 > len(origLines) {
+				return changes, fmt.Errorf("hunk for %q starts past end of file", target)
+			}
+			if origStart-1 >= cursor {
+				out = append(out, origLines[cursor:origStart-1]...)
+				cursor = origStart - 1
+			}
+
+			for i < len(lines) && !strings.HasPrefix(lines[i], "@@") && !strings.HasPrefix(lines[i], "--- ") {
+				hl := lines[i]
+				if hl == "" {
+					i++
+					continue
+				}
+				switch hl[0] {
+				case ' ':
+					out = append(out, hl[1:])
+					cursor++
+				case '-':
+					cursor++
+				case '+':
+					out = append(out, hl[1:])
+				case '\\':
+					// "\ No newline at end of file" - nothing to apply.
+				default:
+					return changes, fmt.Errorf("malformed hunk line: %q", hl)
+				}
+				i++
+			}
+		}
+		out = append(out, origLines[cursor:]...)
+
+		changes = append(changes, FileChange{
+			Path:   target,
+			Before: original,
+			After:  []byte(strings.Join(out, "\n")),
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, fmt.Errorf("no valid diff hunks found")
+	}
+	return changes, nil
+}
+
+// stripDiffHeaderPath removes a leading "a/"/"b/" prefix and any trailing
+// tab-separated timestamp from a "---"/"+++" diff header path.
+func stripDiffHeaderPath(p string) string {
+	p = strings.TrimSpace(strings.SplitN(p, "\t", 2)[0])
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		p = p[2:]
+	}
+	return p
+}
+
+// readFile reads path's current content, returning nil rather than an error
+// if it doesn't exist: that just means the diff is creating it.
+func readFile(path string) []byte {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// resolvePath joins root and rel, rejecting any result that would escape
+// root, since a diff's target paths come from the model and aren't trusted.
+// It works in absolute terms so the containment check holds even when root
+// is "." - filepath.Join cleans away a literal "./" prefix, so comparing
+// against the un-absolutized root would otherwise reject every ordinary
+// relative path.
+func resolvePath(root, rel string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("error resolving workspace root: %w", err)
+	}
+	full := filepath.Join(absRoot, rel)
+	if full != absRoot && !strings.HasPrefix(full, absRoot+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes the workspace root", rel)
+	}
+	return full, nil
+}
+
+// Unified renders a line-level unified diff between before and after,
+// headed by "--- path" / "+++ path", for display in a confirmation prompt.
+// It isn't meant to round-trip through Parse - just to give a reviewer a
+// readable preview - so unchanged runs longer than context are collapsed
+// rather than emitted as real hunk headers.
+func Unified(path string, before, after []byte) string {
+	const context = 3
+
+	a := strings.Split(string(before), "\n")
+	b := strings.Split(string(after), "\n")
+	ops := diffLines(a, b)
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", path, path)
+
+	emitted := 0 // ops before this index have already been written
+	for idx := 0; idx < len(ops); idx++ {
+		if ops[idx].kind == same {
+			continue
+		}
+		// Emit up to `context` lines of leading same-context, collapsing
+		// anything further back that wasn't already written.
+		start := idx
+		for start > emitted && idx-start < context && ops[start-1].kind == same {
+			start--
+		}
+		if start > emitted {
+			out.WriteString("...\n")
+		}
+		idx = start
+		for ; idx < len(ops); idx++ {
+			if ops[idx].kind == same {
+				// Peek ahead: if the same-run is short, keep it as trailing
+				// context; otherwise this hunk is done.
+				run := 0
+				for idx+run < len(ops) && ops[idx+run].kind == same {
+					run++
+				}
+				if run > context {
+					for j := 0; j < context; j++ {
+						fmt.Fprintf(&out, " %s\n", ops[idx+j].text)
+					}
+					emitted = idx + context
+					idx += run - 1
+					break
+				}
+			}
+			switch ops[idx].kind {
+			case same:
+				fmt.Fprintf(&out, " %s\n", ops[idx].text)
+			case removed:
+				fmt.Fprintf(&out, "-%s\n", ops[idx].text)
+			case added:
+				fmt.Fprintf(&out, "+%s\n", ops[idx].text)
+			}
+		}
+	}
+	return out.String()
+}
+
+type lineKind int
+
+const (
+	same lineKind = iota
+	removed
+	added
+)
+
+type lineOp struct {
+	kind lineKind
+	text string
+}
+
+// diffLines walks the longest common subsequence of a and b to produce a
+// minimal same/removed/added script between them.
+func diffLines(a, b []string) []lineOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []lineOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, lineOp{same, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, lineOp{removed, a[i]})
+			i++
+		default:
+			ops = append(ops, lineOp{added, b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, lineOp{removed, a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, lineOp{added, b[j]})
+	}
+	return ops
+}
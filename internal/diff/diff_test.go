@@ -0,0 +1,71 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNewFileHunk(t *testing.T) {
+	// Regression test for fc4b7fe: a "@@ -0,0 +1,N @@" new-file hunk header
+	// used to panic instead of being treated as "no preceding context".
+	root := t.TempDir()
+	d := "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1,2 @@\n+line one\n+line two\n"
+
+	changes, err := Parse(root, d)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("len(changes) = %d, want 1", len(changes))
+	}
+	if changes[0].Before != nil {
+		t.Fatalf("Before = %q, want nil for a new file", changes[0].Before)
+	}
+	if got, want := string(changes[0].After), "line one\nline two\n"; got != want {
+		t.Fatalf("After = %q, want %q", got, want)
+	}
+}
+
+func TestParseOutOfOrderHunks(t *testing.T) {
+	// Regression test for b89a1c4: hunks whose origStart runs backwards
+	// relative to the cursor (e.g. a diff with its hunks out of order) used
+	// to panic on a negative slice index instead of being handled gracefully.
+	root := t.TempDir()
+	path := filepath.Join(root, "file.txt")
+	if err := os.WriteFile(path, []byte("a\nb\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	d := "--- a/file.txt\n+++ b/file.txt\n" +
+		"@@ -3,1 +3,1 @@\n-c\n+C\n" +
+		"@@ -1,1 +1,1 @@\n-a\n+A\n"
+
+	changes, err := Parse(root, d)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("len(changes) = %d, want 1", len(changes))
+	}
+}
+
+func TestParseRejectsPathEscapingRoot(t *testing.T) {
+	root := t.TempDir()
+	d := "--- a/../outside.txt\n+++ b/../outside.txt\n@@ -0,0 +1,1 @@\n+evil\n"
+
+	if _, err := Parse(root, d); err == nil {
+		t.Fatal("Parse succeeded for a diff target escaping root, want an error")
+	}
+}
+
+func TestUnified(t *testing.T) {
+	before := []byte("a\nb\nc\n")
+	after := []byte("a\nB\nc\n")
+
+	out := Unified("file.txt", before, after)
+	if !strings.Contains(out, "-b") || !strings.Contains(out, "+B") {
+		t.Fatalf("Unified output missing expected hunk lines: %q", out)
+	}
+}
@@ -0,0 +1,26 @@
+// Package gemini wraps construction of the Google Generative AI client used
+// by internal/backend's Gemini provider.
+package gemini
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+// DefaultModel is used when the user's config does not specify one.
+const DefaultModel = "gemini-2.0-flash-lite"
+
+// NewClient creates a Gemini client authenticated with apiKey.
+func NewClient(ctx context.Context, apiKey string) (*genai.Client, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("API key is missing")
+	}
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, fmt.Errorf("error creating Gemini client: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,241 @@
+// Package project walks a directory, file, or glob on disk and collects the
+// text files under it that a prompt should be attached with, honoring every
+// .gitignore between the walk root and a file (not just a top-level one) and
+// caller-supplied include/exclude globs. It backs the directory/glob form of
+// the 'file' command's -f/--file handling.
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ignore "github.com/sabhiram/go-gitignore"
+)
+
+// Options controls which files Collect returns.
+type Options struct {
+	// Include, if non-empty, keeps only files whose relative path or base
+	// name matches at least one of these glob patterns.
+	Include []string
+	// Exclude discards files whose relative path or base name matches any
+	// of these glob patterns. Checked after Include.
+	Exclude []string
+}
+
+// File is one file Collect found under a root.
+type File struct {
+	// Path is relative to the root Collect was called with (or just the
+	// base name, when root itself names a single file).
+	Path string
+	// Abs is the file's absolute path on disk.
+	Abs  string
+	Size int64
+}
+
+// Collect expands root (a file, directory, or glob pattern) and returns
+// every text file under it that isn't ignored by a .gitignore between the
+// directory and the file, doesn't look like binary content, and passes opts.
+func Collect(root string, opts Options) ([]File, error) {
+	roots, err := expandRoot(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	seen := map[string]bool{}
+	for _, r := range roots {
+		abs, err := filepath.Abs(r)
+		if err != nil {
+			return nil, fmt.Errorf("error resolving %q: %w", r, err)
+		}
+		r = abs
+		info, err := os.Stat(r)
+		if err != nil {
+			return nil, fmt.Errorf("error accessing %q: %w", r, err)
+		}
+
+		if !info.IsDir() {
+			if seen[r] || isBinary(r) {
+				continue
+			}
+			seen[r] = true
+			files = append(files, File{Path: filepath.Base(r), Abs: r, Size: info.Size()})
+			continue
+		}
+
+		ignores := newIgnoreSet(r)
+
+		walkErr := filepath.WalkDir(r, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(r, path)
+			if err != nil || rel == "." {
+				return err
+			}
+
+			if d.IsDir() {
+				if d.Name() == ".git" || ignores.matches(path) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if ignores.matches(path) || seen[path] || isBinary(path) {
+				return nil
+			}
+
+			entryInfo, err := d.Info()
+			if err != nil {
+				return err
+			}
+			seen[path] = true
+			files = append(files, File{Path: rel, Abs: path, Size: entryInfo.Size()})
+			return nil
+		})
+		if walkErr != nil {
+			return nil, fmt.Errorf("error walking %q: %w", r, walkErr)
+		}
+	}
+
+	return filterGlobs(files, opts), nil
+}
+
+// expandRoot resolves root to the concrete paths Collect should walk: the
+// matches of a glob pattern, or root itself when it's a plain path.
+func expandRoot(root string) ([]string, error) {
+	if strings.ContainsAny(root, "*?[") {
+		matches, err := filepath.Glob(root)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %q: %w", root, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("glob %q matched no files", root)
+		}
+		return matches, nil
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, fmt.Errorf("error accessing %q: %w", root, err)
+	}
+	return []string{root}, nil
+}
+
+// filterGlobs applies opts.Include/Exclude to files.
+func filterGlobs(files []File, opts Options) []File {
+	if len(opts.Include) == 0 && len(opts.Exclude) == 0 {
+		return files
+	}
+	var out []File
+	for _, f := range files {
+		if len(opts.Include) > 0 && !matchesAny(opts.Include, f.Path) {
+			continue
+		}
+		if matchesAny(opts.Exclude, f.Path) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func matchesAny(patterns []string, path string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, filepath.Base(path)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary reports whether the file at path looks like binary content, by
+// checking its first 512 bytes for a NUL byte.
+func isBinary(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) != -1
+}
+
+// ignoreSet resolves every .gitignore between a walk's root and the file
+// being tested, not just a top-level one, mirroring git's own precedence:
+// a file is ignored per the nearest .gitignore with a matching pattern,
+// walking from root down to the file's directory so a deeper file's rules
+// (including a "!" re-include) override a shallower one's. Each file is
+// parsed with go-gitignore, so negation and "**" are supported, unlike a
+// hand-rolled matcher.
+type ignoreSet struct {
+	root  string
+	files map[string]*ignore.GitIgnore // absolute dir -> its .gitignore, nil if it has none
+}
+
+// newIgnoreSet prepares an ignoreSet that will honor every .gitignore under
+// root as Collect's walk reaches it.
+func newIgnoreSet(root string) *ignoreSet {
+	return &ignoreSet{root: root, files: map[string]*ignore.GitIgnore{}}
+}
+
+// forDir returns dir's own .gitignore, loading and caching it on first use.
+// It returns nil, not an error, when dir has no .gitignore: that's the
+// common case and callers just skip it.
+func (s *ignoreSet) forDir(dir string) *ignore.GitIgnore {
+	if gi, ok := s.files[dir]; ok {
+		return gi
+	}
+	gi, err := ignore.CompileIgnoreFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		gi = nil
+	}
+	s.files[dir] = gi
+	return gi
+}
+
+// matches reports whether path, an absolute path under root, is ignored by
+// any .gitignore between root and path's containing directory.
+func (s *ignoreSet) matches(path string) bool {
+	ignored := false
+	for _, dir := range s.ancestorDirs(filepath.Dir(path)) {
+		gi := s.forDir(dir)
+		if gi == nil {
+			continue
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		if matched, pattern := gi.MatchesPathHow(filepath.ToSlash(rel)); matched {
+			ignored = !pattern.Negate
+		}
+	}
+	return ignored
+}
+
+// ancestorDirs returns the chain of directories from root down to dir,
+// inclusive, in root-to-leaf order, so matches can apply the shallowest
+// .gitignore first and let deeper ones override it.
+func (s *ignoreSet) ancestorDirs(dir string) []string {
+	var chain []string
+	for d := dir; ; d = filepath.Dir(d) {
+		chain = append(chain, d)
+		if d == s.root {
+			break
+		}
+		if parent := filepath.Dir(d); parent == d {
+			break
+		}
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
@@ -0,0 +1,67 @@
+// Package prompts loads named preset prompts for the 'file' command's
+// -f/--file handling (e.g. "review", "test-gen") from a user-editable
+// directory in the config dir, seeded with a handful of built-in presets on
+// first use so there's something to copy from.
+package prompts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaults are written to Dir() whenever it's found empty, including the
+// first time it's created.
+var defaults = map[string]string{
+	"summarize":      "Provide a concise summary of the content of this file.",
+	"code-explainer": "Explain the code in this file step-by-step, focusing on its purpose and key logic.",
+	"review":         "Review this code for bugs, style issues, and possible improvements. Be specific and reference file names and line numbers where possible.",
+	"test-gen":       "Write unit tests for this code, covering its main behaviors and edge cases, following the conventions already used in the project.",
+	"doc-gen":        "Write doc comments for the exported identifiers in this code, matching the style already used in the project.",
+}
+
+// Dir returns the directory preset prompts are stored in, creating it (and
+// seeding it with the built-in presets) if necessary:
+// ~/.config/cli-gemini-tool/prompts/.
+func Dir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error resolving user config dir: %w", err)
+	}
+	dir := filepath.Join(configDir, "cli-gemini-tool", "prompts")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("error reading prompts dir: %w", err)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return "", fmt.Errorf("error creating prompts dir: %w", err)
+		}
+		entries = nil
+	}
+
+	if len(entries) == 0 {
+		for name, text := range defaults {
+			if err := os.WriteFile(filepath.Join(dir, name+".txt"), []byte(text+"\n"), 0644); err != nil {
+				return "", fmt.Errorf("error seeding preset %q: %w", name, err)
+			}
+		}
+	}
+	return dir, nil
+}
+
+// Load returns the text of the named preset prompt (the contents of
+// <name>.txt in Dir()).
+func Load(name string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".txt"))
+	if err != nil {
+		return "", fmt.Errorf("unknown preset %q (looked in %s): %w", name, dir, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
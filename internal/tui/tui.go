@@ -0,0 +1,321 @@
+// Package tui implements the interactive conversation view used by the
+// 'chat' command: scrolling message history, highlighted code blocks, a key
+// binding for regenerating the last turn, and one for composing a new prompt
+// in $EDITOR. No key binding edits a turn that has already been sent.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Sairoxs123/cli-gemini-tool/internal/backend"
+	"github.com/Sairoxs123/cli-gemini-tool/internal/codeblock"
+)
+
+var (
+	userStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	modelStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+	codeStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Background(lipgloss.Color("0"))
+	helpStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	inputPrompt = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render("> ")
+)
+
+// turn is a single rendered exchange shown in the scrollback. model fills in
+// incrementally as the response streams in.
+type turn struct {
+	user  string
+	model string
+}
+
+// Model is the bubbletea model backing the interactive chat view.
+type Model struct {
+	ctx      context.Context
+	provider backend.Provider
+	history  []backend.Message
+	viewport viewport.Model
+	turns    []turn
+	input    textinput.Model
+	err      error
+	ready    bool
+	// followTail keeps the viewport pinned to the bottom as new content
+	// streams in. It's cleared as soon as the user scrolls away from the
+	// bottom and restored once they send or regenerate a turn, so an
+	// in-progress response doesn't yank them back down while they're
+	// reading scrollback.
+	followTail bool
+	streaming  bool
+	files      *codeblock.FileWriter
+	writeOpts  codeblock.WriteOptions
+}
+
+// New builds a TUI Model bound to provider. The TUI doesn't have a
+// confirmation UI for proposed file writes yet, so every write is accepted
+// as if --yes had been passed, the same as before this was configurable.
+// Writer progress output is discarded rather than printed: raw stdout writes
+// would corrupt the alt-screen render.
+func New(ctx context.Context, provider backend.Provider) Model {
+	root, err := os.Getwd()
+	if err != nil {
+		root = "."
+	}
+	input := textinput.New()
+	input.Prompt = inputPrompt
+	input.Focus()
+	return Model{
+		ctx:        ctx,
+		provider:   provider,
+		input:      input,
+		followTail: true,
+		writeOpts:  codeblock.WriteOptions{Root: root, Yes: true, Output: io.Discard},
+	}
+}
+
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// responseMsg reports a failure to even start a stream (e.g. the request
+// itself was rejected); a successful response arrives as a streamChunkMsg
+// sequence instead.
+type responseMsg struct {
+	err error
+}
+
+// streamStartMsg carries the channel a newly started stream delivers text on.
+type streamStartMsg struct {
+	ch <-chan backend.StreamChunk
+}
+
+// streamChunkMsg is one piece of an in-progress streamed response, or the
+// final message (done true) once the channel closes.
+type streamChunkMsg struct {
+	ch   <-chan backend.StreamChunk
+	text string
+	err  error
+	done bool
+}
+
+// readStreamCmd reads the next chunk off ch. Update re-issues this after
+// every chunk until the stream reports done, driving the incremental render.
+func readStreamCmd(ch <-chan backend.StreamChunk) tea.Cmd {
+	return func() tea.Msg {
+		chunk, ok := <-ch
+		if !ok {
+			return streamChunkMsg{ch: ch, done: true}
+		}
+		if chunk.Err != nil {
+			return streamChunkMsg{ch: ch, err: chunk.Err, done: true}
+		}
+		return streamChunkMsg{ch: ch, text: chunk.Text}
+	}
+}
+
+func (m Model) sendCmd(input string) tea.Cmd {
+	history := m.history
+	return func() tea.Msg {
+		ch, err := m.provider.StreamMessage(m.ctx, history, []backend.Part{{Text: input}})
+		if err != nil {
+			return responseMsg{err: err}
+		}
+		return streamStartMsg{ch: ch}
+	}
+}
+
+// editInEditorCmd shells out to $EDITOR for composing a long prompt, mirroring
+// the $EDITOR integration lmcli offers for multi-line messages.
+func editInEditorCmd() tea.Cmd {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	tmp, err := os.CreateTemp("", "gemini-prompt-*.md")
+	if err != nil {
+		return func() tea.Msg { return responseMsg{err: err} }
+	}
+	tmp.Close()
+
+	c := exec.Command(editor, tmp.Name())
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		defer os.Remove(tmp.Name())
+		if err != nil {
+			return responseMsg{err: err}
+		}
+		content, readErr := os.ReadFile(tmp.Name())
+		if readErr != nil {
+			return responseMsg{err: readErr}
+		}
+		return composedMsg{text: strings.TrimSpace(string(content))}
+	})
+}
+
+type composedMsg struct{ text string }
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, msg.Height-3)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = msg.Height - 3
+		}
+		m.input.Width = msg.Width - lipgloss.Width(inputPrompt) - 1
+		m.viewport.SetContent(m.renderHistory())
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+		case "ctrl+e":
+			return m, editInEditorCmd()
+		case "ctrl+r":
+			if !m.streaming && len(m.turns) > 0 && m.turns[len(m.turns)-1].model != "" {
+				last := m.turns[len(m.turns)-1]
+				m.turns = m.turns[:len(m.turns)-1]
+				m.history = m.history[:len(m.history)-2] // drop that turn's user+model entries
+				m.turns = append(m.turns, turn{user: last.user})
+				m.history = append(m.history, backend.Message{Role: "user", Parts: []backend.Part{{Text: last.user}}})
+				m.followTail = true
+				m.viewport.SetContent(m.renderHistory())
+				m.viewport.GotoBottom()
+				return m, m.sendCmd(last.user)
+			}
+			return m, nil
+		case "enter":
+			input := strings.TrimSpace(m.input.Value())
+			if input == "" || m.streaming {
+				return m, nil
+			}
+			m.input.Reset()
+			m.turns = append(m.turns, turn{user: input})
+			m.history = append(m.history, backend.Message{Role: "user", Parts: []backend.Part{{Text: input}}})
+			m.followTail = true
+			m.viewport.SetContent(m.renderHistory())
+			m.viewport.GotoBottom()
+			return m, m.sendCmd(input)
+		case "up", "down", "pgup", "pgdown":
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			m.followTail = m.viewport.AtBottom()
+			return m, cmd
+		default:
+			var cmd tea.Cmd
+			m.input, cmd = m.input.Update(msg)
+			return m, cmd
+		}
+
+	case tea.MouseMsg:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		m.followTail = m.viewport.AtBottom()
+		return m, cmd
+
+	case composedMsg:
+		if msg.text == "" {
+			return m, nil
+		}
+		m.turns = append(m.turns, turn{user: msg.text})
+		m.history = append(m.history, backend.Message{Role: "user", Parts: []backend.Part{{Text: msg.text}}})
+		m.followTail = true
+		m.viewport.SetContent(m.renderHistory())
+		m.viewport.GotoBottom()
+		return m, m.sendCmd(msg.text)
+
+	case responseMsg:
+		m.err = msg.err
+		return m, nil
+
+	case streamStartMsg:
+		m.streaming = true
+		m.files = codeblock.NewFileWriter(m.writeOpts)
+		return m, readStreamCmd(msg.ch)
+
+	case streamChunkMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
+		if msg.done {
+			m.streaming = false
+			if m.files != nil {
+				m.files.Close()
+				m.files = nil
+			}
+			if len(m.turns) > 0 {
+				m.history = append(m.history, backend.Message{Role: "model", Parts: []backend.Part{{Text: m.turns[len(m.turns)-1].model}}})
+			}
+			return m, nil
+		}
+		if len(m.turns) > 0 {
+			m.turns[len(m.turns)-1].model += msg.text
+		}
+		if m.files != nil {
+			m.files.Feed(msg.text)
+		}
+		m.viewport.SetContent(m.renderHistory())
+		if m.followTail {
+			m.viewport.GotoBottom()
+		}
+		return m, readStreamCmd(msg.ch)
+	}
+
+	return m, nil
+}
+
+func (m Model) renderHistory() string {
+	var b strings.Builder
+	for _, t := range m.turns {
+		if t.user != "" {
+			b.WriteString(userStyle.Render("You: ") + t.user + "\n")
+		}
+		if t.model != "" {
+			b.WriteString(modelStyle.Render("Gemini: ") + highlightCodeBlocks(t.model) + "\n\n")
+		}
+	}
+	return b.String()
+}
+
+// highlightCodeBlocks renders fenced code blocks with a distinct style so
+// they stand out from prose in the scrollback.
+func highlightCodeBlocks(text string) string {
+	parts := strings.Split(text, "```")
+	var b strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			b.WriteString(codeStyle.Render(part))
+		} else {
+			b.WriteString(part)
+		}
+	}
+	return b.String()
+}
+
+func (m Model) View() string {
+	if !m.ready {
+		return "Initializing..."
+	}
+	help := helpStyle.Render("enter: send  ↑/↓/pgup/pgdn: scroll  ctrl+r: regenerate  ctrl+e: $EDITOR  esc: quit")
+	if m.streaming {
+		help = helpStyle.Render("receiving response...")
+	}
+	if m.err != nil {
+		help = helpStyle.Render(fmt.Sprintf("error: %v", m.err))
+	}
+	return fmt.Sprintf("%s\n%s\n%s", m.viewport.View(), m.input.View(), help)
+}
+
+// Run starts the interactive chat TUI program.
+func Run(ctx context.Context, provider backend.Provider) error {
+	_, err := tea.NewProgram(New(ctx, provider), tea.WithAltScreen(), tea.WithMouseCellMotion()).Run()
+	return err
+}